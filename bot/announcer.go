@@ -0,0 +1,161 @@
+package bot
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// discord rejects messages longer than this
+const maxMessageLength = 2000
+
+// Announcer posts a message for every event in the announcement channel and keeps it up to date
+type Announcer struct {
+	db        *pgxpool.Pool
+	session   *discordgo.Session
+	channelId string
+}
+
+func NewAnnouncer(db *pgxpool.Pool, s *discordgo.Session, channelId string) *Announcer {
+	return &Announcer{
+		db:        db,
+		session:   s,
+		channelId: channelId,
+	}
+}
+
+func (a *Announcer) Run(stop <-chan struct{}, d time.Duration) {
+	t := time.NewTicker(d)
+	for {
+		select {
+		case <-stop:
+			t.Stop()
+			log.Println("Stopping announcer...")
+			return
+		case <-t.C:
+			err := a.announce()
+			if err != nil {
+				log.Printf(err.Error())
+			}
+		}
+	}
+}
+
+func (a *Announcer) announce() error {
+	eventProvider := model.Event{}
+	announcementProvider := model.Announcement{}
+
+	active, err := eventProvider.GetActive(a.db)
+	if err != nil {
+		return err
+	}
+
+	open, err := announcementProvider.GetOpen(a.db)
+	if err != nil {
+		return err
+	}
+
+	announcements := make(map[int64]model.Announcement)
+	var eventIds []int64
+	for _, v := range open {
+		announcements[v.EventId] = v
+		eventIds = append(eventIds, v.EventId)
+	}
+
+	// events that finished since the last run still need their final state posted
+	events := active
+	if len(eventIds) > 0 {
+		finished, err := eventProvider.GetWhereIn(a.db, eventIds)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range finished {
+			if e.IsFinal() {
+				events = append(events, e)
+			}
+		}
+	}
+
+	// one event failing is logged and skipped so it cannot hold back the announcements of the others
+	for _, e := range events {
+		v, ok := announcements[e.Id]
+		if err = a.announceEvent(e, v, ok); err != nil {
+			log.Printf("could not announce event %d: %s", e.Id, err.Error())
+		}
+	}
+
+	return nil
+}
+
+// announceEvent posts the events message or edits the one already posted, a message deleted from discord is posted again
+func (a *Announcer) announceEvent(e model.Event, v model.Announcement, posted bool) error {
+	content, err := a.render(e)
+	if err != nil {
+		return err
+	}
+
+	if posted && v.Content == content {
+		return nil
+	}
+
+	if posted {
+		_, err = a.session.ChannelMessageEdit(v.ChannelId, v.MessageId, content)
+		if err == nil {
+			v.Content = content
+			v.Closed = e.IsFinal()
+			return v.Update(a.db)
+		}
+
+		var restErr *discordgo.RESTError
+		if !errors.As(err, &restErr) || restErr.Message == nil || restErr.Message.Code != discordgo.ErrCodeUnknownMessage {
+			return err
+		}
+	}
+
+	msg, err := a.session.ChannelMessageSend(a.channelId, content)
+	if err != nil {
+		return err
+	}
+
+	v.EventId = e.Id
+	v.ChannelId = msg.ChannelID
+	v.MessageId = msg.ID
+	v.Content = content
+	v.Closed = e.IsFinal()
+
+	if posted {
+		return v.Update(a.db)
+	}
+	return v.Save(a.db)
+}
+
+func (a *Announcer) render(e model.Event) (string, error) {
+	attendance := model.Attendance{}
+	toons, err := attendance.GetAttendees(a.db, e.Id)
+	if err != nil {
+		return "", err
+	}
+
+	content := fmt.Sprintf(">>> **%s** - %s\n__%s - %s__\n%s\n%s\n%s",
+		e.Title,
+		model.EventStatusMap[e.Status],
+		e.EventTime.Format(time.RFC822),
+		e.EndTime().Format(time.Kitchen),
+		e.Description,
+		eq.PrintStats(eq.RaidWideClassCounts(toons)),
+		eq.PrintRoster(toons),
+	)
+
+	if runes := []rune(content); len(runes) > maxMessageLength {
+		content = string(runes[:maxMessageLength-3]) + "..."
+	}
+
+	return content, nil
+}
@@ -1,19 +1,38 @@
 package bot
 
 import (
+	"eqRaidBot/bot/command"
 	"eqRaidBot/db/model"
+	"fmt"
 	"github.com/jackc/pgx/v4/pgxpool"
 	"log"
+	"strings"
 	"time"
 )
 
+// OverlapPolicy decides what the auto attender does with characters already signed up to an overlapping event
+type OverlapPolicy string
+
+const (
+	// OverlapSkip leaves characters out of events that overlap one they are attending
+	OverlapSkip = OverlapPolicy("skip")
+	// OverlapAllow signs characters up to every event regardless of overlap
+	OverlapAllow = OverlapPolicy("allow")
+)
+
 type AutoAttender struct {
-	db *pgxpool.Pool
+	db      *pgxpool.Pool
+	overlap OverlapPolicy
 }
 
-func NewAutoAttender(db *pgxpool.Pool) *AutoAttender {
+func NewAutoAttender(db *pgxpool.Pool, overlap OverlapPolicy) *AutoAttender {
+	if overlap != OverlapAllow {
+		overlap = OverlapSkip
+	}
+
 	return &AutoAttender{
-		db: db,
+		db:      db,
+		overlap: overlap,
 	}
 }
 
@@ -38,6 +57,7 @@ func (a *AutoAttender) registerMembers() error {
 	eventProvider := model.Event{}
 	characterProvider := model.Character{}
 	attendanceProvider := model.Attendance{}
+	capProvider := model.EventClassCap{}
 
 	now := time.Now()
 
@@ -54,22 +74,57 @@ func (a *AutoAttender) registerMembers() error {
 	log.Printf("processing %d events...", len(events))
 
 	for _, event := range events {
+		caps, err := capProvider.GetForEvent(a.db, event.Id)
+		if err != nil {
+			return err
+		}
+
+		attending, err := attendanceProvider.GetAttendees(a.db, event.Id)
+		if err != nil {
+			return err
+		}
+
+		// anyone already waiting gets first claim on open spots
+		attending, err = attendanceProvider.PromoteWaitlist(a.db, event, caps, attending)
+		if err != nil {
+			return err
+		}
+
 		toons, err := characterProvider.GetAllNotAttendingEvent(a.db, event.Id)
 		if err != nil {
 			return err
 		}
 
-		if len(toons) == 0 {
-			continue
+		busy, err := a.busyCharacters(event)
+		if err != nil {
+			return err
 		}
 
-		var attendance []model.Attendance
+		var (
+			attendance []model.Attendance
+			signedUp   []model.Character
+		)
 		for _, v := range toons {
+			if !event.Accepts(v) || busy[v.Id] {
+				continue
+			}
+
+			waitlisted := !event.HasRoom(attending, caps, v)
+			if !waitlisted {
+				attending = append(attending, v)
+			}
+
 			attendance = append(attendance, model.Attendance{
 				EventId:     event.Id,
 				CharacterId: v.Id,
 				Withdrawn:   false,
+				Waitlisted:  waitlisted,
 			})
+			signedUp = append(signedUp, v)
+		}
+
+		if len(attendance) == 0 {
+			continue
 		}
 
 		log.Printf("Saving %d members for event %d", len(attendance), event.Id)
@@ -77,8 +132,80 @@ func (a *AutoAttender) registerMembers() error {
 		if err != nil {
 			return err
 		}
+
+		if err = a.warnMissingFlags(event, signedUp); err != nil {
+			return err
+		}
 	}
 
 	log.Printf("done in %f...", time.Since(now).Seconds())
 	return nil
 }
+
+// warnMissingFlags lets owners know when a character they were just signed up with lacks a flag the event requires
+func (a *AutoAttender) warnMissingFlags(event model.Event, toons []model.Character) error {
+	missing, err := model.MissingFlags(a.db, event.Id, toons)
+	if err != nil {
+		return err
+	}
+
+	for _, c := range toons {
+		flags, ok := missing[c.Id]
+		if !ok {
+			continue
+		}
+
+		n := model.Notification{
+			UserId: c.CreatedBy,
+			Message: fmt.Sprintf("%s was signed up for %s on %s but is missing %s, report flags you have with %s so an officer can verify them.",
+				c.Name,
+				event.Title,
+				event.EventTime.Format(time.RFC822),
+				strings.Join(flags, ", "),
+				command.FlagReport),
+		}
+		if err = n.Save(a.db); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// busyCharacters returns the characters attending an event that overlaps the given one, when the policy cares
+func (a *AutoAttender) busyCharacters(event model.Event) (map[int64]bool, error) {
+	busy := make(map[int64]bool)
+	if a.overlap == OverlapAllow {
+		return busy, nil
+	}
+
+	eventProvider := model.Event{}
+	attendanceProvider := model.Attendance{}
+
+	overlapping, err := eventProvider.GetOverlapping(a.db, event.EventTime, event.EndTime(), event.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(overlapping) == 0 {
+		return busy, nil
+	}
+
+	var eventIds []int64
+	for _, e := range overlapping {
+		eventIds = append(eventIds, e.Id)
+	}
+
+	attendees, err := attendanceProvider.GetAttendeesForEvents(a.db, eventIds)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, toons := range attendees {
+		for _, c := range toons {
+			busy[c.Id] = true
+		}
+	}
+
+	return busy, nil
+}
@@ -0,0 +1,148 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type AccountLinkProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewAccountLinkProvider(db *pgxpool.Pool) *AccountLinkProvider {
+	provider := &AccountLinkProvider{pool: db}
+
+	steps := []Step{
+		provider.link,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *AccountLinkProvider) Name() string {
+	return AccountLink
+}
+
+func (r *AccountLinkProvider) Description() string {
+	return "links two discord accounts to the same player so they share characters e.g. !account-link @old @new, not available to all users"
+}
+
+func (r *AccountLinkProvider) Cleanup() {
+}
+
+func (r *AccountLinkProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *AccountLinkProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *AccountLinkProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to link accounts.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *AccountLinkProvider) link(m *discordgo.MessageCreate) (string, error) {
+	ids := mentionedUserIds(strings.TrimPrefix(m.Content, AccountLink))
+	if len(ids) != 2 || ids[0] == ids[1] {
+		return "", errors.New("please mention the two accounts to link e.g. !account-link @old @new")
+	}
+
+	pa := model.PlayerAccount{}
+	if err := pa.LinkAccounts(r.pool, ids[0], ids[1]); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	linked, err := pa.GetLinked(r.pool, ids[0])
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("These accounts now share their characters: %s", formatMentions(linked)), nil
+}
+
+type AccountUnlinkProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewAccountUnlinkProvider(db *pgxpool.Pool) *AccountUnlinkProvider {
+	provider := &AccountUnlinkProvider{pool: db}
+
+	steps := []Step{
+		provider.unlink,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *AccountUnlinkProvider) Name() string {
+	return AccountUnlink
+}
+
+func (r *AccountUnlinkProvider) Description() string {
+	return "stops an account sharing characters with its linked accounts e.g. !account-unlink @old, not available to all users"
+}
+
+func (r *AccountUnlinkProvider) Cleanup() {
+}
+
+func (r *AccountUnlinkProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *AccountUnlinkProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *AccountUnlinkProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to unlink accounts.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *AccountUnlinkProvider) unlink(m *discordgo.MessageCreate) (string, error) {
+	ids := mentionedUserIds(strings.TrimPrefix(m.Content, AccountUnlink))
+	if len(ids) != 1 {
+		return "", errors.New("please mention the account to unlink e.g. !account-unlink @old")
+	}
+
+	pa := model.PlayerAccount{}
+	if err := pa.Unlink(r.pool, ids[0]); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("<@%s> no longer shares characters with other accounts, it keeps the characters it registered.", ids[0]), nil
+}
+
+func formatMentions(ids []string) string {
+	var mentions []string
+	for _, id := range ids {
+		mentions = append(mentions, fmt.Sprintf("<@%s>", id))
+	}
+	return strings.Join(mentions, ", ")
+}
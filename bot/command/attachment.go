@@ -0,0 +1,45 @@
+package command
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+)
+
+const (
+	// maxAttachmentSize keeps a mistaken upload from being read into memory
+	maxAttachmentSize = 2 << 20
+	// maxLogSize allows for log files covering a few weeks of play
+	maxLogSize = 25 << 20
+)
+
+var attachmentClient = &http.Client{Timeout: 30 * time.Second}
+
+// fetchAttachment downloads the first file attached to the message as long as it is no larger than limit bytes
+func fetchAttachment(m *discordgo.MessageCreate, limit int) ([]byte, error) {
+	if len(m.Attachments) == 0 {
+		return nil, errors.New("please attach the file to the same message as the command")
+	}
+
+	a := m.Attachments[0]
+	if a.Size > limit {
+		return nil, fmt.Errorf("%s is too large to import", a.Filename)
+	}
+
+	res, err := attachmentClient.Get(a.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s returned %s", a.Filename, res.Status)
+	}
+
+	return io.ReadAll(io.LimitReader(res.Body, int64(limit)))
+}
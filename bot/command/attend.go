@@ -14,6 +14,7 @@ import (
 )
 
 const (
+	attendStateStart = 0
 	attendStateChar  = 1
 	attendStateEvent = 2
 	attendStateDone  = 3
@@ -22,105 +23,110 @@ const (
 
 type AttendanceProvider struct {
 	pool     *pgxpool.Pool
-	registry map[string]AttendanceState
+	registry StateRegistry
 	charReg  map[string]map[int]model.Character
 	eventReg map[string]map[int]model.Event
+	manifest *Manifest
 }
 
-type AttendanceState struct {
-	characterId int64
-	eventId     int64
-	state       int
-	userId      string
+type attendState struct {
+	character  model.Character
+	event      model.Event
+	waitlisted bool
+	state      int64
+	userId     string
+	ttl        time.Time
 }
 
-func (r *AttendanceState) toModel() *model.Attendance {
+func (r *attendState) IsComplete() bool {
+	return r.state == attendStateSaved && r.character.Id != 0 && r.event.Id != 0
+}
+
+func (r *attendState) Step() int64 {
+	return r.state
+}
+
+func (r *attendState) TTL() time.Time {
+	return r.ttl
+}
+
+func (r *attendState) toModel() *model.Attendance {
 	return &model.Attendance{
-		EventId:     r.eventId,
-		CharacterId: r.characterId,
+		EventId:     r.event.Id,
+		CharacterId: r.character.Id,
 		Withdrawn:   false,
+		Waitlisted:  r.waitlisted,
 	}
 }
 
-func (r *AttendanceState) IsComplete() bool {
-	return r.state == attendStateSaved && r.characterId != 0 && r.eventId != 0
-}
-
 func NewAttendanceProvider(db *pgxpool.Pool) *AttendanceProvider {
-	return &AttendanceProvider{
-		registry: make(map[string]AttendanceState),
+	provider := &AttendanceProvider{
+		pool:     db,
+		registry: make(StateRegistry),
 		charReg:  make(map[string]map[int]model.Character),
 		eventReg: make(map[string]map[int]model.Event),
-		pool:     db,
 	}
-}
 
-func (r *AttendanceProvider) AttendWorkflow(userId string) *AttendanceState {
-	if v, ok := r.registry[userId]; ok {
-		return &v
-	} else {
-		return nil
+	steps := []Step{
+		provider.start,
+		provider.character,
+		provider.event,
+		provider.done,
 	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
 }
 
-func (r *AttendanceProvider) Step(s *discordgo.Session, m *discordgo.MessageCreate) {
-	c, err := s.UserChannelCreate(m.Author.ID)
-	if err != nil {
-		log.Print(err.Error())
-		return
-	}
+func (r *AttendanceProvider) Name() string {
+	return Attend
+}
 
-	actioned, err := r.init(c, s, m)
-	if err != nil {
-		_ = sendMessage(s, c.ID, err.Error())
-		return
-	}
+func (r *AttendanceProvider) Description() string {
+	return "signs one of your characters up for an event, including characters that withdrew or were not signed up automatically"
+}
 
-	if actioned {
-		return
-	}
+func (r *AttendanceProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.charReg, k)
+		delete(r.eventReg, k)
+	})
+}
 
-	switch r.registry[m.Author.ID].state {
-	case attendStateChar:
-		err := r.charAck(c, s, m)
-		if err != nil {
-			log.Println(err.Error())
-			_ = sendMessage(s, c.ID, "There was a problem fetching the events")
-		}
-	case attendStateEvent:
-		err := r.eventAck(c, s, m)
-		if err != nil {
-			_ = sendMessage(s, c.ID, "There was a problem fetching the events")
-		}
-	case attendStateDone:
-		if err := r.doneAck(s, c, m); err != nil {
-			_ = sendMessage(s, c.ID, "There was an error with your input - please try again")
-		}
+func (r *AttendanceProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
 	}
+}
 
+func (r *AttendanceProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
 }
 
-func (r *AttendanceProvider) init(c *discordgo.Channel, s *discordgo.Session, m *discordgo.MessageCreate) (bool, error) {
-	// check the database to see if they have previously registered
+func (r *AttendanceProvider) start(m *discordgo.MessageCreate) (string, error) {
 	if _, ok := r.registry[m.Author.ID]; !ok {
-		char := model.Character{}
-		toons, err := char.GetByOwner(r.pool, m.Author.ID)
+		c := model.Character{}
+		toons, err := c.GetByOwner(r.pool, m.Author.ID)
 		if err != nil {
 			log.Println(err.Error())
-			return false, errors.New("There was an error with your input - please try again")
+			return "", ErrorInternalError
 		}
 
 		if len(toons) == 0 {
-			return false, errors.New("You have no characters register, please type **!register** to add one.")
+			return "", fmt.Errorf("you have no characters registered, please type **%s** to add one", Register)
 		}
 
-		r.registry[m.Author.ID] = AttendanceState{
+		r.registry[m.Author.ID] = &attendState{
 			state:  attendStateChar,
 			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
 		}
 
 		r.charReg[m.Author.ID] = make(map[int]model.Character)
-		r.eventReg[m.Author.ID] = make(map[int]model.Event)
 
 		var charString []string
 		for i, t := range toons {
@@ -128,138 +134,183 @@ func (r *AttendanceProvider) init(c *discordgo.Channel, s *discordgo.Session, m
 			charString = append(charString, fmt.Sprintf("%d. %s", i, t.Name))
 		}
 
-		if err := sendMessage(s, c.ID, fmt.Sprintf("Hello %s, which character will you be brining?\n%s", m.Author.Username, strings.Join(charString, "\n"))); err != nil {
-			return true, err
-		}
-		return true, nil
+		return fmt.Sprintf("Hello %s, which character will you be bringing?\n%s", m.Author.Username, strings.Join(charString, "\n")), nil
 	}
 
-	return false, nil
+	return "", nil
 }
 
-func (r *AttendanceProvider) charAck(c *discordgo.Channel, s *discordgo.Session, m *discordgo.MessageCreate) error {
+func (r *AttendanceProvider) character(m *discordgo.MessageCreate) (string, error) {
 	i, err := strconv.Atoi(m.Content)
 	if err != nil {
-		return err
-	}
-
-	vs := r.registry[m.Author.ID]
-
-	for k, v := range r.charReg[m.Author.ID] {
-		if k == i {
-			vs.characterId = v.Id
-			break
-		}
+		return "", ErrorInvalidInput
 	}
 
-	if vs.characterId == 0 {
-		return errors.New("invalid character selection")
-	} else {
-		vs.state = attendStateEvent
-		r.registry[m.Author.ID] = vs
+	toon, ok := r.charReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid character selection")
 	}
 
-	event := model.Event{}
-	events, err := event.GetAll(r.pool)
+	e := model.Event{}
+	events, err := e.GetAll(r.pool)
 	if err != nil {
-		return err
+		log.Println(err.Error())
+		return "", ErrorInternalError
 	}
 
-	var eventString []string
+	r.eventReg[m.Author.ID] = make(map[int]model.Event)
 
-	for i, e := range events {
+	// only events whose limits let the character in are offered
+	var eventString []string
+	for _, e := range events {
+		if !e.Accepts(toon) {
+			continue
+		}
+		i := len(r.eventReg[m.Author.ID])
 		r.eventReg[m.Author.ID][i] = e
 		eventString = append(eventString, fmt.Sprintf("%d. %s %s", i, e.Title, e.EventTime.Format(time.RFC822)))
 	}
-	err = sendMessage(s, c.ID, fmt.Sprintf("What event are you signing up for?\n%s", strings.Join(eventString, "\n")))
-	if err != nil {
-		return err
+
+	if len(eventString) == 0 {
+		r.Reset(m)
+		return "", fmt.Errorf("there are no upcoming events %s can attend", toon.Name)
 	}
 
-	return nil
+	v := r.registry[m.Author.ID].(*attendState)
+	v.character = toon
+	v.state = attendStateEvent
+
+	return fmt.Sprintf("What event are you signing %s up for?\n%s", toon.Name, strings.Join(eventString, "\n")), nil
 }
 
-func (r *AttendanceProvider) eventAck(c *discordgo.Channel, s *discordgo.Session, m *discordgo.MessageCreate) error {
+func (r *AttendanceProvider) event(m *discordgo.MessageCreate) (string, error) {
 	i, err := strconv.Atoi(m.Content)
 	if err != nil {
-		return err
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	v := r.registry[m.Author.ID].(*attendState)
+
+	a := model.Attendance{}
+	att, err := a.GetMyAttendanceForEvent(r.pool, e.Id, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
 	}
 
-	vs := r.registry[m.Author.ID]
+	for _, at := range att {
+		if at.CharacterId != v.character.Id {
+			continue
+		}
 
-	for k, v := range r.eventReg[m.Author.ID] {
-		if k == i {
-			vs.eventId = v.Id
-			break
+		if !at.Withdrawn {
+			r.Reset(m)
+			if at.Waitlisted {
+				return "", fmt.Errorf("%s is already on the waitlist for %s", v.character.Name, e.Title)
+			}
+			return "", fmt.Errorf("%s is already signed up for %s", v.character.Name, e.Title)
 		}
 	}
 
-	if vs.eventId == 0 {
-		return errors.New("invalid event selection")
-	} else {
-		vs.state = attendStateDone
-		r.registry[m.Author.ID] = vs
+	attending, err := a.GetAttendees(r.pool, e.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
 	}
 
-	var (
-		chosenChar  model.Character
-		chosenEvent model.Event
-	)
+	c := model.EventClassCap{}
+	caps, err := c.GetForEvent(r.pool, e.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
 
-	for _, v := range r.charReg[m.Author.ID] {
-		if v.Id == r.registry[m.Author.ID].characterId {
-			chosenChar = v
-		}
+	warnings, err := r.warnings(e, v.character)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	v.event = e
+	v.waitlisted = !e.HasRoom(attending, caps, v.character)
+	v.state = attendStateDone
 
+	if v.waitlisted {
+		warnings = append(warnings, fmt.Sprintf("%s is full, %s will be waitlisted until a spot opens.", e.Title, v.character.Name))
 	}
 
-	for _, v := range r.eventReg[m.Author.ID] {
-		if v.Id == r.registry[m.Author.ID].eventId {
-			chosenEvent = v
-		}
+	var warningString string
+	if len(warnings) > 0 {
+		warningString = strings.Join(warnings, "\n") + "\n"
+	}
+
+	return fmt.Sprintf("Does this all look correct?\nCharacter: %s\nEvent: %s %s\n%s1. Yes\n2. No",
+		v.character.Name,
+		e.Title,
+		e.EventTime.Format(time.RFC822),
+		warningString), nil
+}
+
+// warnings lists the flags and resists the character is missing for the event
+func (r *AttendanceProvider) warnings(e model.Event, c model.Character) ([]string, error) {
+	var warnings []string
+
+	missing, err := model.MissingFlags(r.pool, e.Id, []model.Character{c})
+	if err != nil {
+		return nil, err
 	}
 
-	err = sendMessage(s, c.ID, fmt.Sprintf("Does this all look correct?\nCharacter: %s\nEvent: %s\n1. Yes\n2. No",
-		chosenChar.Name,
-		chosenEvent.Title,
-	))
+	if flags, ok := missing[c.Id]; ok {
+		warnings = append(warnings, fmt.Sprintf("%s is missing %s, report flags you have with **%s**.", c.Name, strings.Join(flags, ", "), FlagReport))
+	}
 
+	below, err := belowResists(r.pool, e.Id, []model.Character{c})
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return nil
 
+	if len(below) > 0 {
+		warnings = append(warnings, fmt.Sprintf("Below the required resists: %s, record resists with **%s**.", below[0], Resists))
+	}
+
+	return warnings, nil
 }
 
-func (r *AttendanceProvider) doneAck(s *discordgo.Session, c *discordgo.Channel, m *discordgo.MessageCreate) error {
-	if m.Content == "1" {
-		dat := r.registry[m.Author.ID]
-		err := dat.toModel().Save(r.pool)
+func (r *AttendanceProvider) done(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*attendState)
 
-		if err != nil {
-			return err
+		// a withdrawn row or one saved by the auto attender since the event was picked is signed back up in place
+		if err := v.toModel().SaveOrSignBack(r.pool); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
 		}
 
-		if err = sendMessage(s, c.ID, "You're all signed up."); err != nil {
-			return err
-		}
+		v.state = attendStateSaved
 
-		r.reset(m)
-		return nil
-	} else if m.Content == "2" {
-		if err := sendMessage(s, c.ID, "Resetting all your information"); err != nil {
-			return err
+		msg := fmt.Sprintf("%s is signed up for %s.", v.character.Name, v.event.Title)
+		if v.waitlisted {
+			msg = fmt.Sprintf("%s is on the waitlist for %s.", v.character.Name, v.event.Title)
 		}
 
-		r.reset(m)
-		r.init(c, s, m)
-		return nil
-	}
-	return errors.New("invalid input")
+		r.Reset(m)
 
+		return msg, nil
+	case "2":
+		r.Reset(m)
+		return "Resetting all your information", nil
+	default:
+		return "", ErrorInvalidInput
+	}
 }
 
-func (r *AttendanceProvider) reset(m *discordgo.MessageCreate) {
+func (r *AttendanceProvider) Reset(m *discordgo.MessageCreate) {
 	delete(r.registry, m.Author.ID)
 	delete(r.charReg, m.Author.ID)
 	delete(r.eventReg, m.Author.ID)
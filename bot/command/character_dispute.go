@@ -0,0 +1,143 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type CharacterDisputeProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewCharacterDisputeProvider(db *pgxpool.Pool) *CharacterDisputeProvider {
+	provider := &CharacterDisputeProvider{pool: db}
+
+	steps := []Step{
+		provider.dispute,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CharacterDisputeProvider) Name() string {
+	return CharacterDispute
+}
+
+func (r *CharacterDisputeProvider) Description() string {
+	return "asks the officers to look at a character someone else registered e.g. !character-dispute Soandso that is my cleric"
+}
+
+func (r *CharacterDisputeProvider) Cleanup() {
+}
+
+func (r *CharacterDisputeProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *CharacterDisputeProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *CharacterDisputeProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *CharacterDisputeProvider) dispute(m *discordgo.MessageCreate) (string, error) {
+	args := strings.Fields(strings.TrimPrefix(m.Content, CharacterDispute))
+	if len(args) == 0 {
+		return "", errors.New("please include the character name e.g. !character-dispute Soandso")
+	}
+
+	c := model.Character{}
+	toon, ok, err := c.GetByName(r.pool, args[0])
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if !ok {
+		return "", fmt.Errorf("there is no character called %s, type **%s** to add it", args[0], Register)
+	}
+
+	if toon.CreatedBy == m.Author.ID {
+		return "", fmt.Errorf("%s is already yours", toon.Name)
+	}
+
+	owner := "nobody, it has not been claimed"
+	if toon.IsClaimed() {
+		owner = fmt.Sprintf("<@%s>", toon.CreatedBy)
+	}
+
+	reason := strings.Join(args[1:], " ")
+	if reason == "" {
+		reason = "no reason given"
+	}
+
+	msg := fmt.Sprintf("<@%s> disputes who owns %s - %d %s, it is registered to %s. Reason: %s",
+		m.Author.ID,
+		toon.Name,
+		toon.Level,
+		eq.ClassChoiceMap[toon.Class],
+		owner,
+		reason)
+
+	if err = notifyOfficers(r.pool, msg); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("The officers have been told about your claim to %s.", toon.Name), nil
+}
+
+// notifyOfficers queues a direct message to every authorized user
+func notifyOfficers(db *pgxpool.Pool, msg string) error {
+	for id := range whitelist {
+		n := model.Notification{UserId: id, Message: msg}
+		if err := n.Save(db); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkNameAvailable normalizes a character name and makes sure nobody else has registered it,
+// characterId is the character being renamed and is 0 for new characters
+func checkNameAvailable(db *pgxpool.Pool, input string, characterId int64) (string, error) {
+	name, err := eq.NormalizeName(input)
+	if err != nil {
+		return "", err
+	}
+
+	c := model.Character{}
+	toon, ok, err := c.GetByName(db, name)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	// retired names are free again, GetByName prefers a character still in use
+	if !ok || toon.Id == characterId || toon.Retired {
+		return name, nil
+	}
+
+	return "", nameTakenError(toon)
+}
+
+func nameTakenError(toon model.Character) error {
+	if toon.Retired {
+		return fmt.Errorf("%s has been retired and can no longer be claimed, register it again with **%s** if you are playing it", toon.Name, Register)
+	}
+	if !toon.IsClaimed() {
+		return fmt.Errorf("%s was imported from the guild roster, if it is yours type **%s %s**", toon.Name, Claim, toon.Name)
+	}
+	return fmt.Errorf("%s is already registered by <@%s>. If it is yours type **%s %s** followed by a reason and an officer will sort it out", toon.Name, toon.CreatedBy, CharacterDispute, toon.Name)
+}
@@ -0,0 +1,302 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	charEditStateStart  = 0
+	charEditStateChoose = 1
+	charEditStateField  = 2
+	charEditStateValue  = 3
+	charEditStateDone   = 4
+)
+
+const (
+	charEditFieldName    = 1
+	charEditFieldClass   = 2
+	charEditFieldLevel   = 3
+	charEditFieldAA      = 4
+	charEditFieldType    = 5
+	charEditFieldRace    = 6
+	charEditFieldPromote = 7
+)
+
+type charEditState struct {
+	character model.Character
+	field     int
+	state     int64
+	userId    string
+	ttl       time.Time
+}
+
+func (r *charEditState) IsComplete() bool {
+	return r.state == charEditStateDone
+}
+
+func (r *charEditState) Step() int64 {
+	return r.state
+}
+
+func (r *charEditState) TTL() time.Time {
+	return r.ttl
+}
+
+type CharacterEditProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	charReg  map[string]map[int]model.Character
+	manifest *Manifest
+}
+
+func NewCharacterEditProvider(db *pgxpool.Pool) *CharacterEditProvider {
+	provider := &CharacterEditProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		charReg:  make(map[string]map[int]model.Character),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+		provider.field,
+		provider.value,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CharacterEditProvider) Name() string {
+	return CharacterEdit
+}
+
+func (r *CharacterEditProvider) Description() string {
+	return "changes the name, class, race, level, AA or type of one of your characters"
+}
+
+func (r *CharacterEditProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.charReg, k)
+	})
+}
+
+func (r *CharacterEditProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *CharacterEditProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *CharacterEditProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		c := model.Character{}
+		toons, err := c.GetByOwner(r.pool, m.Author.ID)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(toons) == 0 {
+			return "", fmt.Errorf("you have no characters registered, please type **%s** to add one", Register)
+		}
+
+		r.registry[m.Author.ID] = &charEditState{
+			state:  charEditStateChoose,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+
+		r.charReg[m.Author.ID] = make(map[int]model.Character)
+
+		var charString []string
+		for i, t := range toons {
+			r.charReg[m.Author.ID][i] = t
+			charString = append(charString, fmt.Sprintf("%d. %s - %d %s %s", i, t.Name, t.Level, eq.ClassChoiceMap[t.Class], model.CharTypeMap[t.CharacterType]))
+		}
+
+		return fmt.Sprintf("Which character would you like to edit?\n%s", strings.Join(charString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *CharacterEditProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	c, ok := r.charReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid character selection")
+	}
+
+	v := r.registry[m.Author.ID].(*charEditState)
+	v.character = c
+	v.state = charEditStateField
+
+	return `What would you like to change?
+1. Name
+2. Class
+3. Level
+4. AA
+5. Type
+6. Race
+7. Promote to main`, nil
+}
+
+func (r *CharacterEditProvider) field(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	if i < charEditFieldName || i > charEditFieldPromote {
+		return "", ErrorInvalidInput
+	}
+
+	v := r.registry[m.Author.ID].(*charEditState)
+
+	if i == charEditFieldPromote {
+		return r.promote(m, v.character)
+	}
+
+	v.field = i
+	v.state = charEditStateValue
+
+	switch i {
+	case charEditFieldName:
+		return fmt.Sprintf("What should %s be called?", v.character.Name), nil
+	case charEditFieldClass:
+		return fmt.Sprintf("What is your class? Respond with the number that corresponds. \n%s", eq.ClassChoiceString()), nil
+	case charEditFieldLevel:
+		return "What is your level?", nil
+	case charEditFieldAA:
+		return "How many AA points do you have?", nil
+	case charEditFieldRace:
+		return fmt.Sprintf("What is your race? Respond with the number that corresponds. \n%s", eq.RaceChoiceString(v.character.Class)), nil
+	default:
+		return "How would you describe this character?\n1. Box\n2. Main\n3. Alt", nil
+	}
+}
+
+func (r *CharacterEditProvider) value(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*charEditState)
+	c := v.character
+
+	switch v.field {
+	case charEditFieldName:
+		name, err := checkNameAvailable(r.pool, m.Content, c.Id)
+		if err != nil {
+			return "", err
+		}
+		c.Name = name
+	case charEditFieldClass:
+		classId, err := strconv.ParseInt(m.Content, 10, 64)
+		if err != nil {
+			return "", ErrorInvalidInput
+		}
+
+		if !eq.ActiveRuleset().HasClass(classId) {
+			return "", errors.New("invalid class choice, please try again and pick the number next the corresponding class")
+		}
+
+		if !eq.ValidRace(classId, c.Race) {
+			return "", fmt.Errorf("a %s cannot be a %s, change the race of %s first", eq.RaceChoiceMap[c.Race], eq.ClassChoiceMap[classId], c.Name)
+		}
+		c.Class = classId
+	case charEditFieldRace:
+		raceId, err := parseRace(m.Content, c.Class)
+		if err != nil {
+			return "", err
+		}
+		c.Race = raceId
+	case charEditFieldLevel:
+		i, err := strconv.ParseInt(m.Content, 10, 64)
+		if err != nil {
+			return "", ErrorInvalidInput
+		}
+
+		if i > eq.MaxLevel() || i < 0 {
+			return "", fmt.Errorf("a characters level must be between 0 and %d", eq.MaxLevel())
+		}
+		c.Level = i
+	case charEditFieldAA:
+		i, err := parseAA(m.Content)
+		if err != nil {
+			return "", err
+		}
+		c.AA = i
+	case charEditFieldType:
+		typeId, err := strconv.ParseInt(m.Content, 10, 64)
+		if err != nil {
+			return "", ErrorInvalidInput
+		}
+
+		if _, ok := model.CharTypeMap[typeId]; !ok {
+			return "", errors.New("there was a problem with your input - valid choices are 1, 2 or 3")
+		}
+
+		if err = checkTypeLimit(r.pool, c.CreatedBy, c.Id, typeId); err != nil {
+			return "", err
+		}
+		c.CharacterType = typeId
+	}
+
+	if err := c.Update(r.pool); err != nil {
+		if errors.Is(err, model.ErrNameTaken) {
+			return "", fmt.Errorf("%s has just been registered by someone else", c.Name)
+		}
+		if errors.Is(err, model.ErrMainTaken) || errors.Is(err, model.ErrBoxLimit) {
+			return "", fmt.Errorf("you already have as many %s characters as the guild allows", strings.ToLower(model.CharTypeMap[c.CharacterType]))
+		}
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.Reset(m)
+
+	return fmt.Sprintf("Saved %s - %d %s %s (%d AA).", c.Name, c.Level, eq.RaceClassString(c.Race, c.Class), model.CharTypeMap[c.CharacterType], c.AA), nil
+}
+
+func (r *CharacterEditProvider) promote(m *discordgo.MessageCreate, c model.Character) (string, error) {
+	if c.CharacterType == model.TypeMain {
+		return "", fmt.Errorf("%s is already your main", c.Name)
+	}
+
+	previous := model.CharTypeMap[c.CharacterType]
+	if err := c.PromoteToMain(r.pool); err != nil {
+		if errors.Is(err, model.ErrBoxLimit) {
+			return "", fmt.Errorf("your main cannot become a box, you already have as many boxes as the guild allows")
+		}
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.Reset(m)
+
+	return fmt.Sprintf("%s is now your main, your previous main is now a %s.", c.Name, strings.ToLower(previous)), nil
+}
+
+func (r *CharacterEditProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.charReg, m.Author.ID)
+}
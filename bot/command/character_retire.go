@@ -0,0 +1,167 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	retireStateStart   = 0
+	retireStateChoose  = 1
+	retireStateConfirm = 2
+	retireStateDone    = 3
+)
+
+type retireState struct {
+	character model.Character
+	state     int64
+	userId    string
+	ttl       time.Time
+}
+
+func (r *retireState) IsComplete() bool {
+	return r.state == retireStateDone
+}
+
+func (r *retireState) Step() int64 {
+	return r.state
+}
+
+func (r *retireState) TTL() time.Time {
+	return r.ttl
+}
+
+type CharacterRetireProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	charReg  map[string]map[int]model.Character
+	manifest *Manifest
+}
+
+func NewCharacterRetireProvider(db *pgxpool.Pool) *CharacterRetireProvider {
+	provider := &CharacterRetireProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		charReg:  make(map[string]map[int]model.Character),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+		provider.confirm,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CharacterRetireProvider) Name() string {
+	return CharacterRetire
+}
+
+func (r *CharacterRetireProvider) Description() string {
+	return "retires one of your characters, its attendance history is kept"
+}
+
+func (r *CharacterRetireProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.charReg, k)
+	})
+}
+
+func (r *CharacterRetireProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *CharacterRetireProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *CharacterRetireProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		c := model.Character{}
+		toons, err := c.GetByOwner(r.pool, m.Author.ID)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(toons) == 0 {
+			return "", errors.New("you have no characters to retire")
+		}
+
+		r.registry[m.Author.ID] = &retireState{
+			state:  retireStateChoose,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+
+		r.charReg[m.Author.ID] = make(map[int]model.Character)
+
+		var charString []string
+		for i, t := range toons {
+			r.charReg[m.Author.ID][i] = t
+			charString = append(charString, fmt.Sprintf("%d. %s - %d %s %s", i, t.Name, t.Level, eq.ClassChoiceMap[t.Class], model.CharTypeMap[t.CharacterType]))
+		}
+
+		return fmt.Sprintf("Which character would you like to retire?\n%s", strings.Join(charString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *CharacterRetireProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	c, ok := r.charReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid character selection")
+	}
+
+	v := r.registry[m.Author.ID].(*retireState)
+	v.character = c
+	v.state = retireStateConfirm
+
+	return fmt.Sprintf("%s will be withdrawn from all upcoming events and will no longer be signed up automatically. Are you sure?\n1. Yes\n2. No", c.Name), nil
+}
+
+func (r *CharacterRetireProvider) confirm(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*retireState)
+		if err := v.character.Retire(r.pool); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		r.Reset(m)
+		return fmt.Sprintf("%s has been retired.", v.character.Name), nil
+	case "2":
+		r.Reset(m)
+		return "Canceled retiring your character.", nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *CharacterRetireProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.charReg, m.Author.ID)
+}
@@ -0,0 +1,219 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	transferStateStart     = 0
+	transferStateCharacter = 1
+	transferStateOwner     = 2
+	transferStateConfirm   = 3
+	transferStateDone      = 4
+)
+
+var mentionMatch = regexp.MustCompile(`<@!?(\d+)>|^(\d{15,20})$`)
+
+type transferState struct {
+	character model.Character
+	toUser    string
+	state     int64
+	userId    string
+	ttl       time.Time
+}
+
+func (r *transferState) IsComplete() bool {
+	return r.state == transferStateDone
+}
+
+func (r *transferState) Step() int64 {
+	return r.state
+}
+
+func (r *transferState) TTL() time.Time {
+	return r.ttl
+}
+
+type CharacterTransferProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	charReg  map[string]map[int]model.Character
+	manifest *Manifest
+}
+
+func NewCharacterTransferProvider(db *pgxpool.Pool) *CharacterTransferProvider {
+	provider := &CharacterTransferProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		charReg:  make(map[string]map[int]model.Character),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+		provider.owner,
+		provider.confirm,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CharacterTransferProvider) Name() string {
+	return CharacterTransfer
+}
+
+func (r *CharacterTransferProvider) Description() string {
+	return "asks the officers to move one of your characters to another discord account"
+}
+
+func (r *CharacterTransferProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.charReg, k)
+	})
+}
+
+func (r *CharacterTransferProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *CharacterTransferProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *CharacterTransferProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; ok {
+		return "", nil
+	}
+
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(toons) == 0 {
+		return "", errors.New("you have no characters to transfer")
+	}
+
+	r.registry[m.Author.ID] = &transferState{
+		state:  transferStateCharacter,
+		userId: m.Author.ID,
+		ttl:    time.Now().Add(commandCacheWindow),
+	}
+
+	r.charReg[m.Author.ID] = make(map[int]model.Character)
+
+	var charString []string
+	for i, t := range toons {
+		r.charReg[m.Author.ID][i] = t
+		charString = append(charString, fmt.Sprintf("%d. %s - %d %s %s", i, t.Name, t.Level, eq.ClassChoiceMap[t.Class], model.CharTypeMap[t.CharacterType]))
+	}
+
+	return fmt.Sprintf("Which character would you like to transfer?\n%s", strings.Join(charString, "\n")), nil
+}
+
+func (r *CharacterTransferProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	c, ok := r.charReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid character selection")
+	}
+
+	v := r.registry[m.Author.ID].(*transferState)
+	v.character = c
+	v.state = transferStateOwner
+
+	return fmt.Sprintf("Who should own %s? Mention them e.g. @Soandso or paste their discord user id.", c.Name), nil
+}
+
+func (r *CharacterTransferProvider) owner(m *discordgo.MessageCreate) (string, error) {
+	ids := mentionedUserIds(m.Content)
+	if len(ids) != 1 {
+		return "", errors.New("please mention exactly one discord user")
+	}
+
+	v := r.registry[m.Author.ID].(*transferState)
+	if ids[0] == v.character.CreatedBy {
+		return "", fmt.Errorf("%s already belongs to that account", v.character.Name)
+	}
+
+	v.toUser = ids[0]
+	v.state = transferStateConfirm
+
+	return fmt.Sprintf("Ask the officers to move %s to <@%s>?\n1. Yes\n2. No", v.character.Name, v.toUser), nil
+}
+
+func (r *CharacterTransferProvider) confirm(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*transferState)
+		t := model.CharacterTransfer{
+			CharacterId: v.character.Id,
+			FromUser:    v.character.CreatedBy,
+			ToUser:      v.toUser,
+		}
+		if err := t.Save(r.pool); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		msg := fmt.Sprintf("<@%s> would like to move %s from <@%s> to <@%s>, type **%s** to approve or reject it.",
+			m.Author.ID, v.character.Name, t.FromUser, t.ToUser, TransferReview)
+		if err := notifyOfficers(r.pool, msg); err != nil {
+			log.Println(err.Error())
+		}
+
+		r.Reset(m)
+		return "The officers have been asked to approve the transfer, you will get a message once they decide.", nil
+	case "2":
+		r.Reset(m)
+		return "Canceled the transfer.", nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *CharacterTransferProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.charReg, m.Author.ID)
+}
+
+// mentionedUserIds reads discord user ids from mentions or bare ids in the order they were written
+func mentionedUserIds(content string) []string {
+	var ids []string
+	for _, field := range strings.Fields(content) {
+		match := mentionMatch.FindStringSubmatch(field)
+		if match == nil {
+			continue
+		}
+		if match[1] != "" {
+			ids = append(ids, match[1])
+		} else {
+			ids = append(ids, match[2])
+		}
+	}
+	return ids
+}
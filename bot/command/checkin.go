@@ -0,0 +1,269 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	checkInStateStart      = 0
+	checkInStateEvent      = 1
+	checkInStateCharacters = 2
+	checkInStateDone       = 3
+)
+
+type checkInState struct {
+	eventId int64
+	state   int64
+	userId  string
+	ttl     time.Time
+}
+
+func (r *checkInState) IsComplete() bool {
+	return r.state == checkInStateDone && r.eventId != 0
+}
+
+func (r *checkInState) Step() int64 {
+	return r.state
+}
+
+func (r *checkInState) TTL() time.Time {
+	return r.ttl
+}
+
+type CheckInProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	eventReg map[string]map[int]model.Event
+	charReg  map[string]map[int]model.Character
+	manifest *Manifest
+}
+
+func NewCheckInProvider(db *pgxpool.Pool) *CheckInProvider {
+	provider := &CheckInProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		eventReg: make(map[string]map[int]model.Event),
+		charReg:  make(map[string]map[int]model.Character),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.event,
+		provider.characters,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CheckInProvider) Name() string {
+	return CheckIn
+}
+
+func (r *CheckInProvider) Description() string {
+	return "marks your characters as present at an event that has started"
+}
+
+func (r *CheckInProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.eventReg, k)
+		delete(r.charReg, k)
+	})
+}
+
+func (r *CheckInProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *CheckInProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *CheckInProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		e := model.Event{}
+		events, err := e.GetWhereStatus(r.pool, []int64{model.EventStatusStarted})
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(events) == 0 {
+			return "", errors.New("there are no events in progress to check in to")
+		}
+
+		state := &checkInState{
+			state:  checkInStateEvent,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+		r.registry[m.Author.ID] = state
+
+		r.eventReg[m.Author.ID] = make(map[int]model.Event)
+
+		var eventString []string
+		for i, e := range events {
+			r.eventReg[m.Author.ID][i] = e
+			eventString = append(eventString, fmt.Sprintf("%d. %s %s", i, e.Title, e.EventTime.Format(time.RFC822)))
+		}
+
+		if len(events) == 1 {
+			return r.chooseEvent(m, events[0])
+		}
+
+		return fmt.Sprintf("Which event are you checking in to?\n%s", strings.Join(eventString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *CheckInProvider) event(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	return r.chooseEvent(m, e)
+}
+
+func (r *CheckInProvider) chooseEvent(m *discordgo.MessageCreate, e model.Event) (string, error) {
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.charReg[m.Author.ID] = make(map[int]model.Character)
+
+	var charString []string
+	for _, t := range toons {
+		if !e.Accepts(t) {
+			continue
+		}
+		i := len(r.charReg[m.Author.ID])
+		r.charReg[m.Author.ID][i] = t
+		charString = append(charString, fmt.Sprintf("%d. %s", i, t.Name))
+	}
+
+	if len(charString) == 0 {
+		r.Reset(m)
+		return "", fmt.Errorf("none of your characters can attend %s", e.Title)
+	}
+
+	v := r.registry[m.Author.ID].(*checkInState)
+	v.eventId = e.Id
+	v.state = checkInStateCharacters
+
+	return fmt.Sprintf("Which characters are with you at %s? Respond with a comma separated list e.g. 0,1 or **all**\n%s", e.Title, strings.Join(charString, "\n")), nil
+}
+
+func (r *CheckInProvider) characters(m *discordgo.MessageCreate) (string, error) {
+	chosen, err := chooseCharacters(m.Content, r.charReg[m.Author.ID])
+	if err != nil {
+		return "", err
+	}
+
+	v := r.registry[m.Author.ID].(*checkInState)
+
+	a := model.Attendance{}
+	att, err := a.GetMyAttendanceForEvent(r.pool, v.eventId, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	existing := make(map[int64]model.Attendance)
+	for _, at := range att {
+		existing[at.CharacterId] = at
+	}
+
+	var names []string
+	for _, c := range chosen {
+		at, ok := existing[c.Id]
+		if !ok {
+			at = model.Attendance{
+				EventId:     v.eventId,
+				CharacterId: c.Id,
+				Attended:    true,
+			}
+			err = at.Save(r.pool)
+		} else {
+			at.Withdrawn = false
+			at.Waitlisted = false
+			at.Attended = true
+			err = at.Update(r.pool)
+		}
+
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		names = append(names, c.Name)
+	}
+
+	r.Reset(m)
+
+	return fmt.Sprintf("Checked in %s.", strings.Join(names, ", ")), nil
+}
+
+func (r *CheckInProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.eventReg, m.Author.ID)
+	delete(r.charReg, m.Author.ID)
+}
+
+// chooseCharacters picks characters out of a numbered list from input like "0,2" or "all"
+func chooseCharacters(input string, choices map[int]model.Character) ([]model.Character, error) {
+	if strings.EqualFold(strings.TrimSpace(input), "all") {
+		var chosen []model.Character
+		for i := 0; i < len(choices); i++ {
+			chosen = append(chosen, choices[i])
+		}
+		return chosen, nil
+	}
+
+	var (
+		chosen []model.Character
+		seen   = make(map[int]bool)
+	)
+	for _, part := range strings.Split(input, ",") {
+		i, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return nil, ErrorInvalidInput
+		}
+
+		c, ok := choices[i]
+		if !ok {
+			return nil, errors.New("invalid character selection")
+		}
+
+		if seen[i] {
+			continue
+		}
+		seen[i] = true
+		chosen = append(chosen, c)
+	}
+
+	return chosen, nil
+}
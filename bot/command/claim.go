@@ -0,0 +1,110 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type ClaimProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewClaimProvider(db *pgxpool.Pool) *ClaimProvider {
+	provider := &ClaimProvider{pool: db}
+
+	steps := []Step{
+		provider.claim,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ClaimProvider) Name() string {
+	return Claim
+}
+
+func (r *ClaimProvider) Description() string {
+	return "asks the officers to link a character imported from the guild roster to you e.g. !claim Soandso"
+}
+
+func (r *ClaimProvider) Cleanup() {
+}
+
+func (r *ClaimProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *ClaimProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *ClaimProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *ClaimProvider) claim(m *discordgo.MessageCreate) (string, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(m.Content, Claim))
+	if name == "" {
+		return "", errors.New("please include the character name e.g. !claim Soandso")
+	}
+
+	c := model.Character{}
+	toon, ok, err := c.GetByName(r.pool, name)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if !ok {
+		return "", fmt.Errorf("there is no character called %s, type **%s** to add it", name, Register)
+	}
+
+	if toon.CreatedBy == m.Author.ID {
+		return "", fmt.Errorf("%s is already yours", toon.Name)
+	}
+
+	if toon.IsClaimed() {
+		return "", nameTakenError(toon)
+	}
+
+	ct := model.CharacterTransfer{}
+	pending, err := ct.GetPending(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	for _, p := range pending {
+		if p.CharacterId == toon.Id && p.ToUser == m.Author.ID {
+			return "", fmt.Errorf("you have already asked to claim %s, the officers have not decided yet", toon.Name)
+		}
+	}
+
+	// claims go to the officers like transfers, an empty from user marks a character from the guild roster
+	t := model.CharacterTransfer{
+		CharacterId: toon.Id,
+		FromUser:    "",
+		ToUser:      m.Author.ID,
+	}
+	if err = t.Save(r.pool); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	msg := fmt.Sprintf("<@%s> would like to claim %s - %d %s from the guild roster, type **%s** to approve or reject it.",
+		m.Author.ID, toon.Name, toon.Level, eq.ClassChoiceMap[toon.Class], TransferReview)
+	if err = notifyOfficers(r.pool, msg); err != nil {
+		log.Println(err.Error())
+	}
+
+	return fmt.Sprintf("The officers have been asked to approve your claim on %s, you will get a message once they decide.", toon.Name), nil
+}
@@ -14,14 +14,53 @@ const (
 	actionSent  = commandAction(2)
 	actionSkip  = commandAction(3)
 
-	Register     = "!register"
-	MyCharacters = "!my-characters"
-	Withdraw     = "!withdraw"
-	Split        = "!split"
-	ListEvents   = "!event-list"
-	CreateEvent  = "!event-create"
-	Roster       = "!roster"
-	Help         = "!help"
+	Register          = "!register"
+	MyCharacters      = "!my-characters"
+	CharacterEdit     = "!character-edit"
+	CharacterRetire   = "!character-retire"
+	CharacterDispute  = "!character-dispute"
+	CharacterTransfer = "!character-transfer"
+	TransferReview    = "!transfer-review"
+	AccountLink       = "!account-link"
+	AccountUnlink     = "!account-unlink"
+	Attend            = "!attend"
+	Withdraw          = "!withdraw"
+	Split             = "!split"
+	ListEvents        = "!event-list"
+	CreateEvent       = "!event-create"
+	EditEvent         = "!event-edit"
+	EventHistory      = "!event-history"
+	EventLimits       = "!event-limits"
+	StartEvent        = "!event-start"
+	EndEvent          = "!event-end"
+	CancelEvent       = "!event-cancel"
+	CheckIn           = "!checkin"
+	TemplateCreate    = "!template-create"
+	TemplateList      = "!template-list"
+	TemplateDelete    = "!template-delete"
+	ImportGuild       = "!import-guild"
+	ImportRaid        = "!import-raid"
+	ImportLog         = "!import-log"
+	ImportInventory   = "!import-inventory"
+	Claim             = "!claim"
+	GuildSettings     = "!guild-settings"
+	Roster            = "!roster"
+	Whois             = "!whois"
+	Find              = "!find"
+	Resists           = "!resists"
+	FlagCreate        = "!flag-create"
+	FlagList          = "!flag-list"
+	FlagReport        = "!flag-report"
+	FlagVerify        = "!flag-verify"
+	Epic              = "!epic"
+	EpicReport        = "!epic-report"
+	EpicStep          = "!epic-step"
+	Tradeskill        = "!tradeskill"
+	Crafters          = "!crafters"
+	Has               = "!has"
+	Progress          = "!progress"
+	ProgressReport    = "!progress-report"
+	Help              = "!help"
 
 	commandCacheWindow = 15 * time.Minute
 )
@@ -158,7 +197,7 @@ func processCommand(manifest *Manifest, state int64, m *discordgo.MessageCreate,
 	} else if msg != "" {
 		if len(msg) >= 2000 {
 			size := 1000
-			for _, m := range chunkMsg([]rune(msg), size) {
+			for _, m := range ChunkMsg([]rune(msg), size) {
 				err = sendMessage(s, cId, m)
 				if err != nil {
 					return 0, err
@@ -186,8 +225,9 @@ func sendMessage(s *discordgo.Session, channelId string, msg string) error {
 	return nil
 }
 
+// ChunkMsg splits a message that is too long for discord,
 // find midpoint via size and scan forward for a new line
-func chunkMsg(slice []rune, size int) []string {
+func ChunkMsg(slice []rune, size int) []string {
 	var (
 		pieces     []string
 		breakpoint int
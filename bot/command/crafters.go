@@ -0,0 +1,102 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const craftersListSize = 25
+
+type CraftersProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewCraftersProvider(db *pgxpool.Pool) *CraftersProvider {
+	provider := &CraftersProvider{pool: db}
+
+	steps := []Step{
+		provider.crafters,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CraftersProvider) Name() string {
+	return Crafters
+}
+
+func (r *CraftersProvider) Description() string {
+	return "lists who is best at a tradeskill e.g. !crafters smithing or !crafters jewelcraft 200 for a minimum skill"
+}
+
+func (r *CraftersProvider) Cleanup() {
+}
+
+func (r *CraftersProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *CraftersProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *CraftersProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *CraftersProvider) crafters(m *discordgo.MessageCreate) (string, error) {
+	fields := strings.Fields(strings.TrimPrefix(m.Content, Crafters))
+	if len(fields) == 0 {
+		return "", errors.New("please include a tradeskill e.g. !crafters smithing")
+	}
+
+	// skills like poison making may be typed with a space
+	var min int64
+	if len(fields) > 1 {
+		if n, err := strconv.ParseInt(fields[len(fields)-1], 10, 64); err == nil {
+			if n < 0 || n > eq.MaxTradeskill {
+				return "", fmt.Errorf("the minimum skill must be between 0 and %d", eq.MaxTradeskill)
+			}
+			min = n
+			fields = fields[:len(fields)-1]
+		}
+	}
+
+	name := strings.Join(fields, " ")
+	skill, ok := eq.TradeskillByName(name)
+	if !ok {
+		var names []string
+		for _, s := range eq.Tradeskills {
+			names = append(names, eq.TradeskillNameMap[s])
+		}
+		return "", fmt.Errorf("%s is not a tradeskill, choose from %s", name, strings.Join(names, ", "))
+	}
+
+	ct := model.CharacterTradeskill{}
+	results, err := ct.GetCrafters(r.pool, skill, min, craftersListSize)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(results) == 0 {
+		return fmt.Sprintf("No one has recorded %s yet, members can add theirs with **%s**.", eq.TradeskillNameMap[skill], Tradeskill), nil
+	}
+
+	var lines []string
+	for i, res := range results {
+		lines = append(lines, fmt.Sprintf("%d. %s - %d (%s) <@%s>", i+1, res.Name, res.Value, eq.ClassAbbreviationsMap[res.Class], res.CreatedBy))
+	}
+
+	return fmt.Sprintf("__%s__\n%s", eq.TradeskillNameMap[skill], strings.Join(lines, "\n")), nil
+}
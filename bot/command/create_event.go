@@ -1,39 +1,57 @@
 package command
 
 import (
+	"eqRaidBot/bot/eq"
 	"eqRaidBot/db/model"
+	"errors"
 	"fmt"
 	"log"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/bwmarrin/discordgo"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const eventTimePrompt = `Enter a time for the event.  
+Time must be in the following format: **01/21/2022 07:00PM EST**`
+
 const (
 	eventStateStart     = 0
-	eventStateName      = 1
-	eventStateDesc      = 2
-	eventStateTime      = 3
-	eventStateRepeating = 4
-	eventStateDone      = 5
-	eventStateSaved     = 6
+	eventStateSource    = 1
+	eventStateTemplate  = 2
+	eventStateName      = 3
+	eventStateDesc      = 4
+	eventStateTime      = 5
+	eventStateDuration  = 6
+	eventStateRepeating = 7
+	eventStateDone      = 8
+	eventStateSaved     = 9
 )
 
 type CreateEventProvider struct {
-	pool     *pgxpool.Pool
-	registry StateRegistry
-	manifest *Manifest
+	pool        *pgxpool.Pool
+	registry    StateRegistry
+	templateReg map[string]map[int]model.EventTemplate
+	manifest    *Manifest
 }
 
 type eventState struct {
-	userId      string
-	name        string
-	description string
-	time        time.Time
-	repeats     bool
-	state       int64
-	ttl         time.Time
+	userId       string
+	name         string
+	description  string
+	time         time.Time
+	repeats      bool
+	minLevel     int64
+	allowedTypes []int64
+	resists      map[int64]int64
+	splitCount   int64
+	duration     int64
+	conflicts    string
+	fromTemplate bool // events started from a template only need a time
+	state        int64
+	ttl          time.Time
 }
 
 func (r *eventState) IsComplete() bool {
@@ -54,21 +72,57 @@ func (r *eventState) toModel() *model.Event {
 		Description:  r.description,
 		EventTime:    r.time,
 		IsRepeatable: r.repeats,
+		MinLevel:     r.minLevel,
+		AllowedTypes: r.allowedTypes,
+		SplitCount:   r.splitCount,
+		Duration:     r.duration,
 		CreatedBy:    r.userId,
 	}
 }
 
+func (r *eventState) summary() string {
+	msg := `Does this all look correct?. (1 or 2) 
+Title: %s
+Description: %s
+Time: %s
+Duration: %s
+Repeats weekly: %t
+Minimum level: %d
+Who may attend: %s
+Resists: %s
+Splits: %d
+%s
+1. Yes
+2. No`
+
+	return fmt.Sprintf(msg,
+		r.name,
+		r.description,
+		r.time.String(),
+		formatEventDuration(r.duration),
+		r.repeats,
+		r.minLevel,
+		model.PolicyName(r.allowedTypes),
+		eq.FormatResists(r.resists),
+		r.splitCount,
+		r.conflicts)
+}
+
 func NewCreateEventProvider(db *pgxpool.Pool) *CreateEventProvider {
 	provider := &CreateEventProvider{
-		pool:     db,
-		registry: make(StateRegistry),
+		pool:        db,
+		registry:    make(StateRegistry),
+		templateReg: make(map[string]map[int]model.EventTemplate),
 	}
 
 	steps := []Step{
 		provider.start,
+		provider.source,
+		provider.template,
 		provider.name,
 		provider.description,
 		provider.time,
+		provider.duration,
 		provider.repeating,
 		provider.done,
 	}
@@ -89,6 +143,7 @@ func (r *CreateEventProvider) Description() string {
 func (r *CreateEventProvider) Cleanup() {
 	cleanupCache(r.registry, func(k string) {
 		delete(r.registry, k)
+		delete(r.templateReg, k)
 	})
 }
 
@@ -113,16 +168,89 @@ func (r *CreateEventProvider) Handle(s *discordgo.Session, m *discordgo.MessageC
 
 func (r *CreateEventProvider) start(m *discordgo.MessageCreate) (string, error) {
 	if _, ok := r.registry[m.Author.ID]; !ok {
-		r.registry[m.Author.ID] = &eventState{
-			state:  eventStateName,
-			ttl:    time.Now().Add(commandCacheWindow),
-			userId: m.Author.ID,
+		t := model.EventTemplate{}
+		templates, err := t.GetAll(r.pool)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		state := &eventState{
+			state:        eventStateName,
+			allowedTypes: model.DefaultAllowedTypes,
+			ttl:          time.Now().Add(commandCacheWindow),
+			userId:       m.Author.ID,
+		}
+		r.registry[m.Author.ID] = state
+
+		if len(templates) == 0 {
+			return fmt.Sprintf("Hello %s, what should we call this event?", m.Author.Username), nil
 		}
-		return fmt.Sprintf("Hello %s, what should we call this event?", m.Author.Username), nil
+
+		r.templateReg[m.Author.ID] = make(map[int]model.EventTemplate)
+		for i, t := range templates {
+			r.templateReg[m.Author.ID][i] = t
+		}
+
+		state.state = eventStateSource
+		return fmt.Sprintf("Hello %s, how would you like to create this event? (1 or 2)\n1. Start from scratch\n2. Start from a template", m.Author.Username), nil
 	}
 	return "", nil
 }
 
+func (r *CreateEventProvider) source(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*eventState)
+
+	switch m.Content {
+	case "1":
+		v.state = eventStateName
+		return "What should we call this event?", nil
+	case "2":
+		var templateString []string
+		for i := 0; i < len(r.templateReg[m.Author.ID]); i++ {
+			templateString = append(templateString, formatTemplate(i, r.templateReg[m.Author.ID][i]))
+		}
+
+		v.state = eventStateTemplate
+		return fmt.Sprintf("Which template would you like to start from?\n%s", strings.Join(templateString, "\n")), nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *CreateEventProvider) template(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	t, ok := r.templateReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid template selection")
+	}
+
+	tr := model.TemplateResist{}
+	resists, err := tr.GetForTemplate(r.pool, t.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	v := r.registry[m.Author.ID].(*eventState)
+	v.name = t.Name
+	v.description = t.Description
+	v.repeats = t.IsRepeatable
+	v.minLevel = t.MinLevel
+	v.allowedTypes = t.AllowedTypes
+	v.splitCount = t.SplitCount
+	v.duration = t.Duration
+	v.resists = resists
+	v.fromTemplate = true
+	v.state = eventStateTime
+
+	return eventTimePrompt, nil
+}
+
 func (r *CreateEventProvider) name(m *discordgo.MessageCreate) (string, error) {
 	v := r.registry[m.Author.ID]
 	v.(*eventState).name = m.Content
@@ -138,36 +266,51 @@ func (r *CreateEventProvider) description(m *discordgo.MessageCreate) (string, e
 	v.(*eventState).state = eventStateTime
 	r.registry[m.Author.ID] = v
 
-	return `Enter a time for the event.  
-Time must be in the following format: **01/21/2022 07:00PM EST**`, nil
+	return eventTimePrompt, nil
 }
 
 func (r *CreateEventProvider) time(m *discordgo.MessageCreate) (string, error) {
-	v := r.registry[m.Author.ID]
+	v := r.registry[m.Author.ID].(*eventState)
 
-	t, err := time.Parse("01/02/2006 03:04PM MST", m.Content)
+	t, err := parseEventTime(m.Content)
 	if err != nil {
-		return "", ErrorInvalidInput
+		return "", err
 	}
 
-	z, _ := t.Zone()
+	v.time = t
+
+	if v.fromTemplate {
+		v.conflicts, err = conflictWarning(r.pool, v.time, v.duration, 0)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		v.state = eventStateDone
+		return v.summary(), nil
+	}
+
+	v.state = eventStateDuration
+
+	return eventDurationPrompt, nil
+}
+
+func (r *CreateEventProvider) duration(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*eventState)
 
-	l, err := time.LoadLocation(z)
+	d, err := parseEventDuration(m.Content)
 	if err != nil {
-		return "", ErrorInternalError
+		return "", err
 	}
 
-	t = t.In(l)
-	_, offset := t.Zone()
-	if offset < 0 {
-		offset = offset * -1
+	v.duration = d
+	v.conflicts, err = conflictWarning(r.pool, v.time, v.duration, 0)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
 	}
-	dur := time.Duration(offset) * time.Second
-	t = t.Add(dur)
 
-	v.(*eventState).time = t.UTC()
-	v.(*eventState).state = eventStateRepeating
-	r.registry[m.Author.ID] = v
+	v.state = eventStateRepeating
 
 	return `Does the event repeat weekly?. (1 or 2) 
 1. Yes
@@ -189,31 +332,26 @@ func (r *CreateEventProvider) repeating(m *discordgo.MessageCreate) (string, err
 	v.(*eventState).state = eventStateDone
 	r.registry[m.Author.ID] = v
 
-	msg := `Does this all look correct?. (1 or 2) 
-Title: %s
-Description: %s
-Time: %s
-Repeats weekly: %t
-
-1. Yes
-2. No`
-
-	return fmt.Sprintf(msg,
-		v.(*eventState).name,
-		v.(*eventState).description,
-		v.(*eventState).time.String(),
-		v.(*eventState).repeats), nil
-
+	return v.(*eventState).summary(), nil
 }
 
 func (r *CreateEventProvider) done(m *discordgo.MessageCreate) (string, error) {
 	if m.Content == "1" {
 		dat := r.registry[m.Author.ID].(*eventState)
-		err := dat.toModel().Save(r.pool)
+		e := dat.toModel()
+		err := e.Save(r.pool)
 		if err != nil {
 			log.Printf(err.Error())
 			return "", ErrorInternalError
 		}
+
+		if len(dat.resists) > 0 && e.Id != 0 {
+			er := model.EventResist{}
+			if err = er.ReplaceForEvent(r.pool, e.Id, dat.resists); err != nil {
+				log.Println(err.Error())
+				return "", ErrorInternalError
+			}
+		}
 		r.Reset(m)
 		return "The event has been saved", nil
 	} else if m.Content == "2" {
@@ -226,4 +364,5 @@ func (r *CreateEventProvider) done(m *discordgo.MessageCreate) (string, error) {
 
 func (r *CreateEventProvider) Reset(m *discordgo.MessageCreate) {
 	delete(r.registry, m.Author.ID)
+	delete(r.templateReg, m.Author.ID)
 }
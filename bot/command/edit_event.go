@@ -0,0 +1,261 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	editEventStateStart   = 0
+	editEventStateEvent   = 1
+	editEventStateField   = 2
+	editEventStateValue   = 3
+	editEventStateConfirm = 4
+	editEventStateDone    = 5
+)
+
+const (
+	editEventFieldTitle       = 1
+	editEventFieldDescription = 2
+	editEventFieldTime        = 3
+	editEventFieldDuration    = 4
+)
+
+type editEventState struct {
+	event  model.Event
+	field  int
+	state  int64
+	userId string
+	ttl    time.Time
+}
+
+func (r *editEventState) IsComplete() bool {
+	return r.state == editEventStateDone
+}
+
+func (r *editEventState) Step() int64 {
+	return r.state
+}
+
+func (r *editEventState) TTL() time.Time {
+	return r.ttl
+}
+
+type EditEventProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	eventReg map[string]map[int]model.Event
+	manifest *Manifest
+}
+
+func NewEditEventProvider(db *pgxpool.Pool) *EditEventProvider {
+	provider := &EditEventProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		eventReg: make(map[string]map[int]model.Event),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+		provider.field,
+		provider.value,
+		provider.confirm,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *EditEventProvider) Name() string {
+	return EditEvent
+}
+
+func (r *EditEventProvider) Description() string {
+	return "changes the title, description, time or duration of an event, not available to all users"
+}
+
+func (r *EditEventProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.eventReg, k)
+	})
+}
+
+func (r *EditEventProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *EditEventProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to edit events.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *EditEventProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		e := model.Event{}
+		events, err := e.GetAll(r.pool)
+		if err != nil {
+			return "", ErrorInternalError
+		}
+
+		if len(events) == 0 {
+			return "", errors.New("there are no events to edit")
+		}
+
+		r.registry[m.Author.ID] = &editEventState{
+			state:  editEventStateEvent,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+
+		r.eventReg[m.Author.ID] = make(map[int]model.Event)
+
+		var eventString []string
+		for i, e := range events {
+			r.eventReg[m.Author.ID][i] = e
+			eventString = append(eventString, fmt.Sprintf("%d. %s %s", i, e.Title, e.EventTime.Format(time.RFC822)))
+		}
+
+		return fmt.Sprintf("Which event would you like to edit?\n%s", strings.Join(eventString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *EditEventProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	v := r.registry[m.Author.ID].(*editEventState)
+	v.event = e
+	v.state = editEventStateField
+
+	return `What would you like to change?
+1. Title
+2. Description
+3. Time
+4. Duration`, nil
+}
+
+func (r *EditEventProvider) field(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	if i < editEventFieldTitle || i > editEventFieldDuration {
+		return "", ErrorInvalidInput
+	}
+
+	v := r.registry[m.Author.ID].(*editEventState)
+	v.field = i
+	v.state = editEventStateValue
+
+	switch i {
+	case editEventFieldTitle:
+		return fmt.Sprintf("The current title is **%s**, what should it be?", v.event.Title), nil
+	case editEventFieldDescription:
+		return fmt.Sprintf("The current description is **%s**, what should it be?", v.event.Description), nil
+	case editEventFieldTime:
+		return eventTimePrompt, nil
+	default:
+		return eventDurationPrompt, nil
+	}
+}
+
+func (r *EditEventProvider) value(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*editEventState)
+
+	switch v.field {
+	case editEventFieldTitle:
+		v.event.Title = m.Content
+	case editEventFieldDescription:
+		v.event.Description = m.Content
+	case editEventFieldTime:
+		t, err := parseEventTime(m.Content)
+		if err != nil {
+			return "", err
+		}
+		v.event.EventTime = t
+	case editEventFieldDuration:
+		d, err := parseEventDuration(m.Content)
+		if err != nil {
+			return "", err
+		}
+		v.event.Duration = d
+	}
+
+	conflicts, err := conflictWarning(r.pool, v.event.EventTime, v.event.Duration, v.event.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	v.state = editEventStateConfirm
+
+	msg := `Does this all look correct?. (1 or 2)
+Title: %s
+Description: %s
+Time: %s
+Duration: %s
+%s
+1. Yes
+2. No`
+
+	return fmt.Sprintf(msg,
+		v.event.Title,
+		v.event.Description,
+		v.event.EventTime.String(),
+		formatEventDuration(v.event.Duration),
+		conflicts), nil
+}
+
+func (r *EditEventProvider) confirm(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*editEventState)
+		if err := v.event.Update(r.pool); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		r.Reset(m)
+		return "The event has been updated", nil
+	case "2":
+		r.Reset(m)
+		return "Discarded your changes", nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *EditEventProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.eventReg, m.Author.ID)
+}
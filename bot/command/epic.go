@@ -0,0 +1,139 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type EpicProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewEpicProvider(db *pgxpool.Pool) *EpicProvider {
+	provider := &EpicProvider{pool: db}
+
+	steps := []Step{
+		provider.epic,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *EpicProvider) Name() string {
+	return Epic
+}
+
+func (r *EpicProvider) Description() string {
+	return "tracks epic progress e.g. !epic Clericbot lists the steps and !epic Clericbot 3 records the third step as done, on its own lists your characters"
+}
+
+func (r *EpicProvider) Cleanup() {
+}
+
+func (r *EpicProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *EpicProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *EpicProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *EpicProvider) epic(m *discordgo.MessageCreate) (string, error) {
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(toons) == 0 {
+		return "", fmt.Errorf("you have no characters registered, please type **%s** to add one", Register)
+	}
+
+	var ids []int64
+	for _, t := range toons {
+		ids = append(ids, t.Id)
+	}
+
+	ce := model.CharacterEpic{}
+	progress, err := ce.GetForCharacters(r.pool, ids)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	es := model.EpicStep{}
+	catalog, err := es.GetCatalog(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(m.Content, Epic))
+	if len(fields) == 0 {
+		var lines []string
+		for _, t := range toons {
+			p := progress[t.Id]
+			lines = append(lines, fmt.Sprintf("%s - %s: %s", t.Name, eq.EpicWeaponMap[t.Class], eq.EpicProgress(t.Class, catalog[t.Class], p.Step, p.Finished)))
+		}
+		return fmt.Sprintf("__Epic progress__\n%s", strings.Join(lines, "\n")), nil
+	}
+
+	var toon *model.Character
+	for i := range toons {
+		if strings.EqualFold(toons[i].Name, fields[0]) {
+			toon = &toons[i]
+		}
+	}
+
+	if toon == nil {
+		return "", fmt.Errorf("%s is not one of your characters", fields[0])
+	}
+
+	milestones := catalog[toon.Class]
+	steps := eq.EpicSteps(toon.Class, milestones)
+
+	if len(fields) == 1 {
+		p := progress[toon.Id]
+		var lines []string
+		for i, step := range steps {
+			done := " "
+			if p.Finished || (i < len(milestones) && int64(i) < p.Step) {
+				done = "x"
+			}
+			lines = append(lines, fmt.Sprintf("[%s] %d. %s", done, i+1, step))
+		}
+		return fmt.Sprintf("__%s - %s__\n%s\n\nRecord a step with **%s %s <step>**, 0 clears it.", toon.Name, eq.EpicWeaponMap[toon.Class], strings.Join(lines, "\n"), Epic, toon.Name), nil
+	}
+
+	step, err := strconv.ParseInt(fields[1], 10, 64)
+	if err != nil || step < 0 || step > int64(len(steps)) {
+		return "", fmt.Errorf("the step must be a number between 0 and %d", len(steps))
+	}
+
+	// the last step is the weapon, finishing it counts every catalog step as done
+	ce = model.CharacterEpic{CharacterId: toon.Id, Step: step, Finished: step == int64(len(steps))}
+	if ce.Finished {
+		ce.Step = int64(len(milestones))
+	}
+
+	if err = ce.Save(r.pool); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("Recorded %s as %s.", toon.Name, eq.EpicProgress(toon.Class, milestones, ce.Step, ce.Finished)), nil
+}
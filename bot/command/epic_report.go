@@ -0,0 +1,110 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type EpicReportProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewEpicReportProvider(db *pgxpool.Pool) *EpicReportProvider {
+	provider := &EpicReportProvider{pool: db}
+
+	steps := []Step{
+		provider.report,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *EpicReportProvider) Name() string {
+	return EpicReport
+}
+
+func (r *EpicReportProvider) Description() string {
+	return "shows the epic progress of every main and anyone else working on one grouped by class e.g. !epic-report CLR, not available to all users"
+}
+
+func (r *EpicReportProvider) Cleanup() {
+}
+
+func (r *EpicReportProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *EpicReportProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *EpicReportProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to view the epic report.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *EpicReportProvider) report(m *discordgo.MessageCreate) (string, error) {
+	var only int64
+	if name := strings.TrimSpace(strings.TrimPrefix(m.Content, EpicReport)); name != "" {
+		class, ok := eq.ClassByAbbreviation(name)
+		if !ok {
+			return "", fmt.Errorf("%s is not a class", name)
+		}
+		only = class
+	}
+
+	es := model.EpicStep{}
+	catalog, err := es.GetCatalog(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	ce := model.CharacterEpic{}
+	results, err := ce.GetProgress(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	byClass := make(map[int64][]string)
+	for _, res := range results {
+		if only != 0 && res.Class != only {
+			continue
+		}
+		byClass[res.Class] = append(byClass[res.Class], fmt.Sprintf("%s (%s) <@%s> - %s",
+			res.Name,
+			model.CharTypeMap[res.CharacterType],
+			res.CreatedBy,
+			eq.EpicProgress(res.Class, catalog[res.Class], res.Step, res.Finished)))
+	}
+
+	if len(byClass) == 0 {
+		return "No one is working on an epic yet.", nil
+	}
+
+	var sections []string
+	for class := int64(1); class <= int64(len(eq.ClassChoiceMap)); class++ {
+		lines, ok := byClass[class]
+		if !ok {
+			continue
+		}
+		sections = append(sections, fmt.Sprintf("__%s - %s__\n%s", eq.ClassChoiceMap[class], eq.EpicWeaponMap[class], strings.Join(lines, "\n")))
+	}
+
+	return strings.Join(sections, "\n\n"), nil
+}
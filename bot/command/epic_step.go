@@ -0,0 +1,109 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type EpicStepProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewEpicStepProvider(db *pgxpool.Pool) *EpicStepProvider {
+	provider := &EpicStepProvider{pool: db}
+
+	steps := []Step{
+		provider.step,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *EpicStepProvider) Name() string {
+	return EpicStep
+}
+
+func (r *EpicStepProvider) Description() string {
+	return "keeps the steps of a class epic e.g. !epic-step CLR Peacekeeper Staff adds a step, !epic-step CLR remove drops the last one and !epic-step CLR lists them, not available to all users"
+}
+
+func (r *EpicStepProvider) Cleanup() {
+}
+
+func (r *EpicStepProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *EpicStepProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *EpicStepProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to change the epic steps.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *EpicStepProvider) step(m *discordgo.MessageCreate) (string, error) {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(m.Content, EpicStep)), " ", 2)
+	if fields[0] == "" {
+		return "", errors.New("please include a class e.g. !epic-step CLR Peacekeeper Staff")
+	}
+
+	class, ok := eq.ClassByAbbreviation(fields[0])
+	if !ok {
+		return "", fmt.Errorf("%s is not a class", fields[0])
+	}
+
+	es := model.EpicStep{Class: class, CreatedBy: m.Author.ID}
+
+	var name string
+	if len(fields) == 2 {
+		name = strings.TrimSpace(fields[1])
+	}
+
+	switch {
+	case name == "":
+	case strings.EqualFold(name, "remove"):
+		if err := es.DeleteLast(r.pool, class); err != nil {
+			if errors.Is(err, model.ErrNoEpicSteps) {
+				return "", fmt.Errorf("the %s epic has no steps to remove", eq.ClassChoiceMap[class])
+			}
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+	default:
+		es.Name = name
+		if err := es.Save(r.pool); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+	}
+
+	catalog, err := es.GetCatalog(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	var lines []string
+	for i, step := range eq.EpicSteps(class, catalog[class]) {
+		lines = append(lines, fmt.Sprintf("%d. %s", i+1, step))
+	}
+
+	return fmt.Sprintf("__%s - %s__\n%s", eq.ClassChoiceMap[class], eq.EpicWeaponMap[class], strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,211 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const historyPageSize = 3
+
+type EventHistoryProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewEventHistoryProvider(db *pgxpool.Pool) *EventHistoryProvider {
+	provider := &EventHistoryProvider{pool: db}
+
+	steps := []Step{
+		provider.list,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *EventHistoryProvider) Name() string {
+	return EventHistory
+}
+
+func (r *EventHistoryProvider) Description() string {
+	return "pages through completed events e.g. !event-history Nagafen from=01/02/2022 to=02/02/2022 page=2"
+}
+
+func (r *EventHistoryProvider) Cleanup() {
+}
+
+func (r *EventHistoryProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *EventHistoryProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *EventHistoryProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *EventHistoryProvider) list(m *discordgo.MessageCreate) (string, error) {
+	filter, page, err := parseHistoryFilter(strings.TrimPrefix(m.Content, EventHistory))
+	if err != nil {
+		return "", err
+	}
+
+	e := model.Event{}
+	events, err := e.GetHistory(r.pool, filter)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(events) == 0 {
+		return "No completed events found.", nil
+	}
+
+	var history []string
+	for _, event := range events {
+		str, err := r.eventSummary(event)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		history = append(history, str)
+	}
+
+	return fmt.Sprintf("__Completed events, page %d__\n%s", page, strings.Join(history, "\n")), nil
+}
+
+func (r *EventHistoryProvider) eventSummary(event model.Event) (string, error) {
+	a := model.Attendance{}
+	label := "Attended"
+	toons, err := a.GetAttended(r.pool, event.Id)
+	if err != nil {
+		return "", err
+	}
+
+	// events from before check in existed only know who signed up
+	if len(toons) == 0 {
+		label = "Signed up"
+		toons, err = a.GetAttendees(r.pool, event.Id)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var names []string
+	for _, t := range toons {
+		names = append(names, fmt.Sprintf("(%s)%s", eq.ClassAbbreviationsMap[t.Class], t.Name))
+	}
+
+	str := fmt.Sprintf("**%s %s**: %s\n%s\n**%s** - %d: %s\n",
+		event.EventTime.Format(time.RFC822),
+		event.Title,
+		event.Description,
+		eq.PrintStats(eq.RaidWideClassCounts(toons)),
+		label,
+		len(names),
+		strings.Join(names, ", "))
+
+	ek := model.EventKill{}
+	kills, err := ek.GetForEvent(r.pool, event.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(kills) > 0 {
+		var mobs []string
+		for _, k := range kills {
+			mobs = append(mobs, k.Mob)
+		}
+		str += fmt.Sprintf("**Kills**: %s\n", strings.Join(mobs, ", "))
+	}
+
+	el := model.EventLoot{}
+	loot, err := el.GetForEvent(r.pool, event.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(loot) > 0 {
+		var items []string
+		for _, l := range loot {
+			items = append(items, fmt.Sprintf("%s (%s)", l.Item, l.CharacterName))
+		}
+		str += fmt.Sprintf("**Loot**: %s\n", strings.Join(items, ", "))
+	}
+
+	es := model.EventSplit{}
+	splits, err := es.GetForEvent(r.pool, event.Id)
+	if err != nil {
+		return "", err
+	}
+
+	if len(splits) > 0 {
+		var stats []map[int64]int
+		for _, split := range splits {
+			var raid []model.Character
+			for _, group := range split {
+				raid = append(raid, group...)
+			}
+			stats = append(stats, eq.RaidWideClassCounts(raid))
+		}
+		str += fmt.Sprintf("__Published split__%s", formatSplits(splits, stats, nil))
+	}
+
+	return str, nil
+}
+
+// parseHistoryFilter reads from=, to= and page= options, any other words are matched against the event title
+func parseHistoryFilter(input string) (model.EventHistoryFilter, int, error) {
+	filter := model.EventHistoryFilter{
+		Limit: historyPageSize,
+	}
+	page := 1
+
+	var title []string
+	for _, field := range strings.Fields(input) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			title = append(title, field)
+			continue
+		}
+
+		key := strings.ToLower(kv[0])
+		switch key {
+		case "from", "to":
+			t, err := time.Parse("01/02/2006", kv[1])
+			if err != nil {
+				return filter, 0, errors.New("dates must be in the following format: 01/21/2022")
+			}
+			if key == "from" {
+				filter.From = t
+			} else {
+				// include the whole of the last day
+				filter.To = t.Add(24 * time.Hour)
+			}
+		case "page":
+			n, err := strconv.Atoi(kv[1])
+			if err != nil || n < 1 {
+				return filter, 0, errors.New("page must be a number greater than 0")
+			}
+			page = n
+		default:
+			return filter, 0, fmt.Errorf("%s is not a known filter", kv[0])
+		}
+	}
+
+	filter.Title = strings.Join(title, " ")
+	filter.Offset = (page - 1) * historyPageSize
+
+	return filter, page, nil
+}
@@ -0,0 +1,373 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	limitsStateStart  = 0
+	limitsStateEvent  = 1
+	limitsStateLimits = 2
+	limitsStateDone   = 3
+)
+
+const limitsHelp = `Enter the limits for this event on one line, any limit left out is removed.
+**max=40** - maximum number of attendees
+**level=55** - minimum character level
+**policy=alts** - who may attend, one of %s
+**types=main,box** - character types that may attend, for mixes no policy covers
+**classes=CLR:6,BRD:4** - per class caps
+**resists=FR:150,CR:100** - minimum resists, members record theirs with **!resists**
+**flags=vp,sleeper** - required keys or flags, see **!flag-list**
+Respond with **none** to remove all limits.`
+
+type limitsState struct {
+	eventId int64
+	state   int64
+	userId  string
+	ttl     time.Time
+}
+
+func (r *limitsState) IsComplete() bool {
+	return r.state == limitsStateDone && r.eventId != 0
+}
+
+func (r *limitsState) Step() int64 {
+	return r.state
+}
+
+func (r *limitsState) TTL() time.Time {
+	return r.ttl
+}
+
+// eventLimits is the parsed form of an officers limit input
+type eventLimits struct {
+	maxAttendees int64
+	minLevel     int64
+	allowedTypes []int64
+	classCaps    map[int64]int64
+	resists      map[int64]int64
+	flagKeys     []string
+}
+
+type EventLimitsProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	eventReg map[string]map[int]model.Event
+	manifest *Manifest
+}
+
+func NewEventLimitsProvider(db *pgxpool.Pool) *EventLimitsProvider {
+	provider := &EventLimitsProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		eventReg: make(map[string]map[int]model.Event),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.event,
+		provider.limits,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *EventLimitsProvider) Name() string {
+	return EventLimits
+}
+
+func (r *EventLimitsProvider) Description() string {
+	return "sets attendee, level, type, class, resist and flag limits on an event, not available to all users"
+}
+
+func (r *EventLimitsProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.eventReg, k)
+	})
+}
+
+func (r *EventLimitsProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *EventLimitsProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to change event limits.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *EventLimitsProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		e := model.Event{}
+		events, err := e.GetAll(r.pool)
+		if err != nil {
+			return "", ErrorInternalError
+		}
+
+		if len(events) == 0 {
+			return "", errors.New("there are no events to limit")
+		}
+
+		r.registry[m.Author.ID] = &limitsState{
+			state:  limitsStateEvent,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+
+		r.eventReg[m.Author.ID] = make(map[int]model.Event)
+
+		var eventString []string
+		for i, e := range events {
+			r.eventReg[m.Author.ID][i] = e
+			eventString = append(eventString, fmt.Sprintf("%d. %s %s", i, e.Title, e.EventTime.Format(time.RFC822)))
+		}
+
+		return fmt.Sprintf("Which event would you like to limit?\n%s", strings.Join(eventString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *EventLimitsProvider) event(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	c := model.EventClassCap{}
+	caps, err := c.GetForEvent(r.pool, e.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	er := model.EventResist{}
+	resists, err := er.GetForEvent(r.pool, e.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	ef := model.EventFlag{}
+	flags, err := ef.GetForEvent(r.pool, e.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	v := r.registry[m.Author.ID].(*limitsState)
+	v.eventId = e.Id
+	v.state = limitsStateLimits
+
+	help := fmt.Sprintf(limitsHelp, strings.Join(model.PolicyNames(), ", "))
+
+	return fmt.Sprintf("Current limits: %s\n\n%s", formatEventLimits(e, caps, resists, flags), help), nil
+}
+
+func (r *EventLimitsProvider) limits(m *discordgo.MessageCreate) (string, error) {
+	limits, err := parseEventLimits(m.Content)
+	if err != nil {
+		return "", err
+	}
+
+	var flags []model.Flag
+	if len(limits.flagKeys) > 0 {
+		f := model.Flag{}
+		flags, err = f.GetByKeys(r.pool, limits.flagKeys)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(flags) != len(limits.flagKeys) {
+			return "", fmt.Errorf("one of %s is not a flag, type **%s** to see every flag", strings.Join(limits.flagKeys, ", "), FlagList)
+		}
+	}
+
+	v := r.registry[m.Author.ID].(*limitsState)
+
+	var event model.Event
+	for _, e := range r.eventReg[m.Author.ID] {
+		if e.Id == v.eventId {
+			event = e
+		}
+	}
+
+	event.MaxAttendees = limits.maxAttendees
+	event.MinLevel = limits.minLevel
+	event.AllowedTypes = limits.allowedTypes
+
+	var flagIds []int64
+	for _, f := range flags {
+		flagIds = append(flagIds, f.Id)
+	}
+
+	// sign ups that no longer fit the new limits are withdrawn or waitlisted in the same transaction
+	if err = event.SaveLimits(r.pool, limits.classCaps, limits.resists, flagIds); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.Reset(m)
+
+	return fmt.Sprintf("Updated the limits of %s: %s", event.Title, formatEventLimits(event, limits.classCaps, limits.resists, flags)), nil
+}
+
+func (r *EventLimitsProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.eventReg, m.Author.ID)
+}
+
+func parseEventLimits(input string) (*eventLimits, error) {
+	limits := &eventLimits{
+		allowedTypes: model.DefaultAllowedTypes,
+		classCaps:    make(map[int64]int64),
+		resists:      make(map[int64]int64),
+	}
+
+	if strings.EqualFold(strings.TrimSpace(input), "none") {
+		return limits, nil
+	}
+
+	for _, field := range strings.Fields(input) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("could not understand %s, limits look like key=value", field)
+		}
+
+		switch strings.ToLower(kv[0]) {
+		case "max":
+			n, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil || n < 0 {
+				return nil, errors.New("max must be a positive number")
+			}
+			limits.maxAttendees = n
+		case "level":
+			n, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil || n < 0 || n > eq.MaxLevel() {
+				return nil, fmt.Errorf("level must be between 0 and %d", eq.MaxLevel())
+			}
+			limits.minLevel = n
+		case "policy":
+			types, ok := model.PolicyByName(kv[1])
+			if !ok {
+				return nil, fmt.Errorf("%s is not a policy, choose one of %s", kv[1], strings.Join(model.PolicyNames(), ", "))
+			}
+			limits.allowedTypes = types
+		case "types":
+			var types []int64
+			for _, name := range strings.Split(kv[1], ",") {
+				t, ok := model.CharTypeByName(name)
+				if !ok {
+					return nil, fmt.Errorf("%s is not a character type", name)
+				}
+				types = append(types, t)
+			}
+			limits.allowedTypes = types
+		case "classes":
+			for _, pair := range strings.Split(kv[1], ",") {
+				classCap := strings.SplitN(pair, ":", 2)
+				if len(classCap) != 2 {
+					return nil, fmt.Errorf("could not understand %s, class caps look like CLR:6", pair)
+				}
+
+				class, ok := eq.ClassByAbbreviation(classCap[0])
+				if !ok {
+					return nil, fmt.Errorf("%s is not a class", classCap[0])
+				}
+
+				n, err := strconv.ParseInt(classCap[1], 10, 64)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("the cap for %s must be a positive number", classCap[0])
+				}
+				limits.classCaps[class] = n
+			}
+		case "resists":
+			resists, err := eq.ParseResists(kv[1])
+			if err != nil {
+				return nil, err
+			}
+			limits.resists = resists
+		case "flags":
+			seen := make(map[string]bool)
+			for _, name := range strings.Split(kv[1], ",") {
+				key, err := eq.NormalizeFlagKey(name)
+				if err != nil {
+					return nil, err
+				}
+				if !seen[key] {
+					limits.flagKeys = append(limits.flagKeys, key)
+					seen[key] = true
+				}
+			}
+		default:
+			return nil, fmt.Errorf("%s is not a known limit", kv[0])
+		}
+	}
+
+	return limits, nil
+}
+
+func formatEventLimits(e model.Event, caps map[int64]int64, resists map[int64]int64, flags []model.Flag) string {
+	var classes []string
+	for class, n := range caps {
+		classes = append(classes, fmt.Sprintf("%s:%d", eq.ClassAbbreviationsMap[class], n))
+	}
+	sort.Strings(classes)
+
+	max := "unlimited"
+	if e.MaxAttendees > 0 {
+		max = strconv.FormatInt(e.MaxAttendees, 10)
+	}
+
+	classString := "none"
+	if len(classes) > 0 {
+		classString = strings.Join(classes, ", ")
+	}
+
+	flagString := "none"
+	if len(flags) > 0 {
+		var keys []string
+		for _, f := range flags {
+			keys = append(keys, f.Key)
+		}
+		flagString = strings.Join(keys, ", ")
+	}
+
+	return fmt.Sprintf("max %s, level %d+, policy %s, class caps %s, resists %s, flags %s",
+		max,
+		e.MinLevel,
+		model.PolicyName(e.AllowedTypes),
+		classString,
+		eq.FormatResists(resists),
+		flagString)
+}
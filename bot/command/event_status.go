@@ -0,0 +1,188 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	statusStateStart = 0
+	statusStateEvent = 1
+	statusStateDone  = 2
+)
+
+type statusState struct {
+	state  int64
+	userId string
+	ttl    time.Time
+}
+
+func (r *statusState) IsComplete() bool {
+	return r.state == statusStateDone
+}
+
+func (r *statusState) Step() int64 {
+	return r.state
+}
+
+func (r *statusState) TTL() time.Time {
+	return r.ttl
+}
+
+// EventStatusProvider moves an event from one lifecycle state to another, it backs the start, end and cancel commands
+type EventStatusProvider struct {
+	pool        *pgxpool.Pool
+	registry    StateRegistry
+	eventReg    map[string]map[int]model.Event
+	manifest    *Manifest
+	name        string
+	description string
+	from        []int64
+	to          int64
+}
+
+func newEventStatusProvider(db *pgxpool.Pool, name, description string, from []int64, to int64) *EventStatusProvider {
+	provider := &EventStatusProvider{
+		pool:        db,
+		registry:    make(StateRegistry),
+		eventReg:    make(map[string]map[int]model.Event),
+		name:        name,
+		description: description,
+		from:        from,
+		to:          to,
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.event,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func NewStartEventProvider(db *pgxpool.Pool) *EventStatusProvider {
+	return newEventStatusProvider(db,
+		StartEvent,
+		"marks a scheduled event as started and opens check in, not available to all users",
+		[]int64{model.EventStatusScheduled},
+		model.EventStatusStarted)
+}
+
+func NewEndEventProvider(db *pgxpool.Pool) *EventStatusProvider {
+	return newEventStatusProvider(db,
+		EndEvent,
+		"marks a started event as completed, started events stay open until ended. Not available to all users",
+		[]int64{model.EventStatusStarted},
+		model.EventStatusCompleted)
+}
+
+func NewCancelEventProvider(db *pgxpool.Pool) *EventStatusProvider {
+	return newEventStatusProvider(db,
+		CancelEvent,
+		"cancels an event that has not completed, not available to all users",
+		[]int64{model.EventStatusScheduled, model.EventStatusStarted},
+		model.EventStatusCancelled)
+}
+
+func (r *EventStatusProvider) Name() string {
+	return r.name
+}
+
+func (r *EventStatusProvider) Description() string {
+	return r.description
+}
+
+func (r *EventStatusProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.eventReg, k)
+	})
+}
+
+func (r *EventStatusProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *EventStatusProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to change the state of events.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *EventStatusProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		e := model.Event{}
+		events, err := e.GetWhereStatus(r.pool, r.from)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(events) == 0 {
+			return "", errors.New("there are no events that can be changed")
+		}
+
+		r.registry[m.Author.ID] = &statusState{
+			state:  statusStateEvent,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+
+		r.eventReg[m.Author.ID] = make(map[int]model.Event)
+
+		var eventString []string
+		for i, e := range events {
+			r.eventReg[m.Author.ID][i] = e
+			eventString = append(eventString, fmt.Sprintf("%d. %s %s (%s)", i, e.Title, e.EventTime.Format(time.RFC822), model.EventStatusMap[e.Status]))
+		}
+
+		return fmt.Sprintf("Which event should be marked %s?\n%s", strings.ToLower(model.EventStatusMap[r.to]), strings.Join(eventString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *EventStatusProvider) event(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	if err = e.UpdateStatus(r.pool, r.to); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.Reset(m)
+
+	return fmt.Sprintf("%s is now %s.", e.Title, strings.ToLower(model.EventStatusMap[r.to])), nil
+}
+
+func (r *EventStatusProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.eventReg, m.Author.ID)
+}
@@ -0,0 +1,73 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const eventDurationPrompt = "How many hours will the event last? e.g. 3 or 2.5"
+
+// parseEventTime reads times like 01/21/2022 07:00PM EST and returns them in UTC
+func parseEventTime(input string) (time.Time, error) {
+	t, err := time.Parse("01/02/2006 03:04PM MST", input)
+	if err != nil {
+		return time.Time{}, ErrorInvalidInput
+	}
+
+	z, _ := t.Zone()
+
+	l, err := time.LoadLocation(z)
+	if err != nil {
+		return time.Time{}, ErrorInternalError
+	}
+
+	t = t.In(l)
+	_, offset := t.Zone()
+	if offset < 0 {
+		offset = offset * -1
+	}
+	dur := time.Duration(offset) * time.Second
+	t = t.Add(dur)
+
+	return t.UTC(), nil
+}
+
+// parseEventDuration reads a number of hours and returns the duration in minutes
+func parseEventDuration(input string) (int64, error) {
+	h, err := strconv.ParseFloat(strings.TrimSpace(input), 64)
+	if err != nil || h <= 0 || h > 24 {
+		return 0, fmt.Errorf("the duration must be a number of hours between 0 and 24")
+	}
+
+	return int64(h * 60), nil
+}
+
+func formatEventDuration(minutes int64) string {
+	return (time.Duration(minutes) * time.Minute).String()
+}
+
+// conflictWarning describes any events that overlap the given window, it is empty when there are none
+func conflictWarning(db *pgxpool.Pool, start time.Time, minutes int64, excludeId int64) (string, error) {
+	e := model.Event{}
+	end := start.Add(time.Duration(minutes) * time.Minute)
+	events, err := e.GetOverlapping(db, start, end, excludeId)
+	if err != nil {
+		return "", err
+	}
+
+	if len(events) == 0 {
+		return "", nil
+	}
+
+	var conflicts []string
+	for _, v := range events {
+		conflicts = append(conflicts, fmt.Sprintf("%s %s - %s", v.Title, v.EventTime.Format(time.RFC822), v.EndTime().Format(time.Kitchen)))
+	}
+
+	return fmt.Sprintf("**Warning** this overlaps with:\n%s", strings.Join(conflicts, "\n")), nil
+}
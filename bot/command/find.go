@@ -0,0 +1,206 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const findPageSize = 15
+
+type FindProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewFindProvider(db *pgxpool.Pool) *FindProvider {
+	provider := &FindProvider{pool: db}
+
+	steps := []Step{
+		provider.find,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *FindProvider) Name() string {
+	return Find
+}
+
+func (r *FindProvider) Description() string {
+	return "searches characters e.g. !find class=SHM,DRU race=OGR,TRL level=58-60 type=main aa=10- attended=30 page=2, attended is a number of days"
+}
+
+func (r *FindProvider) Cleanup() {
+}
+
+func (r *FindProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *FindProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *FindProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *FindProvider) find(m *discordgo.MessageCreate) (string, error) {
+	filter, page, err := parseCharacterFilter(strings.TrimPrefix(m.Content, Find))
+	if err != nil {
+		return "", err
+	}
+
+	c := model.Character{}
+	results, err := c.Find(r.pool, filter)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(results) == 0 {
+		return "No characters match your search.", nil
+	}
+
+	var lines []string
+	for _, res := range results {
+		last := "never"
+		if res.LastAttended != nil {
+			last = res.LastAttended.Format("01/02/2006")
+		}
+
+		owner := "unclaimed"
+		if res.IsClaimed() {
+			owner = fmt.Sprintf("<@%s>", res.CreatedBy)
+		}
+
+		class := eq.ClassAbbreviationsMap[res.Class]
+		if res.Race != eq.RaceUnknown {
+			class = fmt.Sprintf("%s %s", eq.RaceAbbreviationsMap[res.Race], class)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s - %d %s %s (%d AA) %s, last attended %s",
+			res.Name,
+			res.Level,
+			class,
+			model.CharTypeMap[res.CharacterType],
+			res.AA,
+			owner,
+			last))
+	}
+
+	return fmt.Sprintf("__Characters, page %d__\n%s", page, strings.Join(lines, "\n")), nil
+}
+
+// parseCharacterFilter reads class=, race=, level=, type=, aa=, attended= and page= options,
+// ranges are written min-max and either end may be left off
+func parseCharacterFilter(input string) (model.CharacterFilter, int, error) {
+	filter := model.CharacterFilter{
+		Limit: findPageSize,
+	}
+	page := 1
+
+	for _, field := range strings.Fields(input) {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return filter, 0, fmt.Errorf("could not understand %s, filters look like key=value", field)
+		}
+
+		switch strings.ToLower(kv[0]) {
+		case "class":
+			for _, name := range strings.Split(kv[1], ",") {
+				class, ok := eq.ClassByAbbreviation(name)
+				if !ok {
+					return filter, 0, fmt.Errorf("%s is not a class", name)
+				}
+				filter.Classes = append(filter.Classes, class)
+			}
+		case "race":
+			for _, name := range strings.Split(kv[1], ",") {
+				race, ok := eq.RaceByName(name)
+				if !ok {
+					return filter, 0, fmt.Errorf("%s is not a race", name)
+				}
+				filter.Races = append(filter.Races, race)
+			}
+		case "type":
+			for _, name := range strings.Split(kv[1], ",") {
+				t, ok := model.CharTypeByName(name)
+				if !ok {
+					return filter, 0, fmt.Errorf("%s is not a character type", name)
+				}
+				filter.Types = append(filter.Types, t)
+			}
+		case "level":
+			min, max, err := parseRange(kv[1])
+			if err != nil {
+				return filter, 0, fmt.Errorf("level %s", err.Error())
+			}
+			filter.MinLevel, filter.MaxLevel = min, max
+		case "aa":
+			min, max, err := parseRange(kv[1])
+			if err != nil {
+				return filter, 0, fmt.Errorf("aa %s", err.Error())
+			}
+			filter.MinAA, filter.MaxAA = min, max
+		case "attended":
+			days, err := strconv.Atoi(kv[1])
+			if err != nil || days < 1 {
+				return filter, 0, errors.New("attended must be a number of days greater than 0")
+			}
+			filter.AttendedSince = time.Now().AddDate(0, 0, -days)
+		case "page":
+			n, err := strconv.Atoi(kv[1])
+			if err != nil || n < 1 {
+				return filter, 0, errors.New("page must be a number greater than 0")
+			}
+			page = n
+		default:
+			return filter, 0, fmt.Errorf("%s is not a known filter", kv[0])
+		}
+	}
+
+	filter.Offset = (page - 1) * findPageSize
+
+	return filter, page, nil
+}
+
+// parseRange reads 58, 58-60, 58- or -60, max is nil when the range has no upper end
+func parseRange(input string) (int64, *int64, error) {
+	bounds := strings.SplitN(input, "-", 2)
+
+	var vals []*int64
+	for _, b := range bounds {
+		if b == "" {
+			vals = append(vals, nil)
+			continue
+		}
+		n, err := strconv.ParseInt(b, 10, 64)
+		if err != nil || n < 0 {
+			return 0, nil, errors.New("must be a number or a range like 58-60")
+		}
+		vals = append(vals, &n)
+	}
+
+	var min int64
+	if vals[0] != nil {
+		min = *vals[0]
+	}
+
+	max := vals[len(vals)-1]
+	if max != nil && min > *max {
+		return 0, nil, errors.New("range must go from low to high")
+	}
+
+	return min, max, nil
+}
@@ -0,0 +1,87 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type FlagCreateProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewFlagCreateProvider(db *pgxpool.Pool) *FlagCreateProvider {
+	provider := &FlagCreateProvider{pool: db}
+
+	steps := []Step{
+		provider.create,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *FlagCreateProvider) Name() string {
+	return FlagCreate
+}
+
+func (r *FlagCreateProvider) Description() string {
+	return "adds a key or flag to the catalog e.g. !flag-create vp Veeshan's Peak key, not available to all users"
+}
+
+func (r *FlagCreateProvider) Cleanup() {
+}
+
+func (r *FlagCreateProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *FlagCreateProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *FlagCreateProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to create flags.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *FlagCreateProvider) create(m *discordgo.MessageCreate) (string, error) {
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(m.Content, FlagCreate)), " ", 2)
+	if len(fields) != 2 || strings.TrimSpace(fields[1]) == "" {
+		return "", errors.New("please include a key and a description e.g. !flag-create vp Veeshan's Peak key")
+	}
+
+	key, err := eq.NormalizeFlagKey(fields[0])
+	if err != nil {
+		return "", err
+	}
+
+	f := model.Flag{
+		Key:         key,
+		Description: strings.TrimSpace(fields[1]),
+		CreatedBy:   m.Author.ID,
+	}
+
+	if err = f.Save(r.pool); err != nil {
+		if errors.Is(err, model.ErrFlagExists) {
+			return "", fmt.Errorf("there is already a flag called %s", key)
+		}
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("Added **%s** - %s. Members can report it with **%s Name %s**.", f.Key, f.Description, FlagReport, f.Key), nil
+}
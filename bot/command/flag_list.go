@@ -0,0 +1,70 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type FlagListProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewFlagListProvider(db *pgxpool.Pool) *FlagListProvider {
+	provider := &FlagListProvider{pool: db}
+
+	steps := []Step{
+		provider.list,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *FlagListProvider) Name() string {
+	return FlagList
+}
+
+func (r *FlagListProvider) Description() string {
+	return "lists the keys and flags the guild tracks"
+}
+
+func (r *FlagListProvider) Cleanup() {
+}
+
+func (r *FlagListProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *FlagListProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *FlagListProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *FlagListProvider) list(m *discordgo.MessageCreate) (string, error) {
+	f := model.Flag{}
+	flags, err := f.GetAll(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(flags) == 0 {
+		return "No flags found.", nil
+	}
+
+	var lines []string
+	for _, f := range flags {
+		lines = append(lines, fmt.Sprintf("**%s** - %s", f.Key, f.Description))
+	}
+
+	return fmt.Sprintf("__Flags__\n%s", strings.Join(lines, "\n")), nil
+}
@@ -0,0 +1,110 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type FlagReportProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewFlagReportProvider(db *pgxpool.Pool) *FlagReportProvider {
+	provider := &FlagReportProvider{pool: db}
+
+	steps := []Step{
+		provider.report,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *FlagReportProvider) Name() string {
+	return FlagReport
+}
+
+func (r *FlagReportProvider) Description() string {
+	return "reports that one of your characters has a key or flag for an officer to verify e.g. !flag-report Clericbot vp"
+}
+
+func (r *FlagReportProvider) Cleanup() {
+}
+
+func (r *FlagReportProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *FlagReportProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *FlagReportProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *FlagReportProvider) report(m *discordgo.MessageCreate) (string, error) {
+	fields := strings.Fields(strings.TrimPrefix(m.Content, FlagReport))
+	if len(fields) != 2 {
+		return "", fmt.Errorf("please include the character and the flag e.g. %s Clericbot vp, type **%s** to see every flag", FlagReport, FlagList)
+	}
+
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	var toon *model.Character
+	for i := range toons {
+		if strings.EqualFold(toons[i].Name, fields[0]) {
+			toon = &toons[i]
+		}
+	}
+
+	if toon == nil {
+		return "", fmt.Errorf("%s is not one of your characters", fields[0])
+	}
+
+	key, err := eq.NormalizeFlagKey(fields[1])
+	if err != nil {
+		return "", err
+	}
+
+	f := model.Flag{}
+	flags, err := f.GetByKeys(r.pool, []string{key})
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(flags) == 0 {
+		return "", fmt.Errorf("there is no flag called %s, type **%s** to see every flag", key, FlagList)
+	}
+
+	cf := model.CharacterFlag{CharacterId: toon.Id, FlagId: flags[0].Id}
+	created, err := cf.Report(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if !created {
+		return fmt.Sprintf("%s already has %s recorded.", toon.Name, key), nil
+	}
+
+	msg := fmt.Sprintf("<@%s> reports that %s has %s (%s), type **%s** to review it.", m.Author.ID, toon.Name, key, flags[0].Description, FlagVerify)
+	if err = notifyOfficers(r.pool, msg); err != nil {
+		log.Println(err.Error())
+	}
+
+	return fmt.Sprintf("Recorded %s for %s, it will count once an officer verifies it.", key, toon.Name), nil
+}
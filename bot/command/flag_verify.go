@@ -0,0 +1,233 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	flagVerifyStateStart    = 0
+	flagVerifyStateReport   = 1
+	flagVerifyStateDecision = 2
+	flagVerifyStateDone     = 3
+)
+
+type flagVerifyState struct {
+	report    model.CharacterFlag
+	character model.Character
+	flag      model.Flag
+	state     int64
+	userId    string
+	ttl       time.Time
+}
+
+func (r *flagVerifyState) IsComplete() bool {
+	return r.state == flagVerifyStateDone
+}
+
+func (r *flagVerifyState) Step() int64 {
+	return r.state
+}
+
+func (r *flagVerifyState) TTL() time.Time {
+	return r.ttl
+}
+
+type FlagVerifyProvider struct {
+	pool      *pgxpool.Pool
+	registry  StateRegistry
+	reportReg map[string]map[int]model.CharacterFlag
+	charReg   map[string]map[int64]model.Character
+	flagReg   map[string]map[int64]model.Flag
+	manifest  *Manifest
+}
+
+func NewFlagVerifyProvider(db *pgxpool.Pool) *FlagVerifyProvider {
+	provider := &FlagVerifyProvider{
+		pool:      db,
+		registry:  make(StateRegistry),
+		reportReg: make(map[string]map[int]model.CharacterFlag),
+		charReg:   make(map[string]map[int64]model.Character),
+		flagReg:   make(map[string]map[int64]model.Flag),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+		provider.decide,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *FlagVerifyProvider) Name() string {
+	return FlagVerify
+}
+
+func (r *FlagVerifyProvider) Description() string {
+	return "verifies or rejects flags reported by members, not available to all users"
+}
+
+func (r *FlagVerifyProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.reportReg, k)
+		delete(r.charReg, k)
+		delete(r.flagReg, k)
+	})
+}
+
+func (r *FlagVerifyProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *FlagVerifyProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to verify flags.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *FlagVerifyProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; ok {
+		return "", nil
+	}
+
+	cf := model.CharacterFlag{}
+	reports, err := cf.GetUnverified(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(reports) == 0 {
+		return "There are no flags waiting for review.", nil
+	}
+
+	var ids []int64
+	for _, rep := range reports {
+		ids = append(ids, rep.CharacterId)
+	}
+
+	c := model.Character{}
+	toons, err := c.GetWhereIn(r.pool, ids)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	f := model.Flag{}
+	flags, err := f.GetAll(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.charReg[m.Author.ID] = make(map[int64]model.Character)
+	for _, t := range toons {
+		r.charReg[m.Author.ID][t.Id] = t
+	}
+
+	r.flagReg[m.Author.ID] = make(map[int64]model.Flag)
+	for _, f := range flags {
+		r.flagReg[m.Author.ID][f.Id] = f
+	}
+
+	r.registry[m.Author.ID] = &flagVerifyState{
+		state:  flagVerifyStateReport,
+		userId: m.Author.ID,
+		ttl:    time.Now().Add(commandCacheWindow),
+	}
+
+	r.reportReg[m.Author.ID] = make(map[int]model.CharacterFlag)
+
+	var reportString []string
+	for i, rep := range reports {
+		r.reportReg[m.Author.ID][i] = rep
+		t := r.charReg[m.Author.ID][rep.CharacterId]
+		reportString = append(reportString, fmt.Sprintf("%d. %s has %s, reported by <@%s> on %s",
+			i,
+			t.Name,
+			r.flagReg[m.Author.ID][rep.FlagId].Key,
+			t.CreatedBy,
+			rep.ReportedAt.Format("01/02/2006")))
+	}
+
+	return fmt.Sprintf("Which report would you like to review?\n%s", strings.Join(reportString, "\n")), nil
+}
+
+func (r *FlagVerifyProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	rep, ok := r.reportReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid report selection")
+	}
+
+	v := r.registry[m.Author.ID].(*flagVerifyState)
+	v.report = rep
+	v.character = r.charReg[m.Author.ID][rep.CharacterId]
+	v.flag = r.flagReg[m.Author.ID][rep.FlagId]
+	v.state = flagVerifyStateDecision
+
+	return fmt.Sprintf("Does %s have %s (%s)?\n1. Verify\n2. Reject", v.character.Name, v.flag.Key, v.flag.Description), nil
+}
+
+func (r *FlagVerifyProvider) decide(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*flagVerifyState)
+
+	var msg string
+	switch m.Content {
+	case "1":
+		if err := v.report.Verify(r.pool, m.Author.ID); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		msg = fmt.Sprintf("%s for %s has been verified.", v.flag.Key, v.character.Name)
+	case "2":
+		if err := v.report.Reject(r.pool); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		msg = fmt.Sprintf("%s for %s was rejected, report it again with **%s** once you have it.", v.flag.Key, v.character.Name, FlagReport)
+	default:
+		return "", ErrorInvalidInput
+	}
+
+	n := model.Notification{UserId: v.character.CreatedBy, Message: msg}
+	if err := n.Save(r.pool); err != nil {
+		log.Println(err.Error())
+	}
+
+	r.Reset(m)
+
+	return msg, nil
+}
+
+func (r *FlagVerifyProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.reportReg, m.Author.ID)
+	delete(r.charReg, m.Author.ID)
+	delete(r.flagReg, m.Author.ID)
+}
@@ -0,0 +1,126 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// maxBoxesLimit is a sanity cap, nobody runs more than a full group of boxes
+const maxBoxesLimit = 5
+
+type GuildSettingsProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewGuildSettingsProvider(db *pgxpool.Pool) *GuildSettingsProvider {
+	provider := &GuildSettingsProvider{pool: db}
+
+	steps := []Step{
+		provider.settings,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *GuildSettingsProvider) Name() string {
+	return GuildSettings
+}
+
+func (r *GuildSettingsProvider) Description() string {
+	return "shows or changes the guild settings e.g. !guild-settings boxes=2 expansion=luclin, not available to all users"
+}
+
+func (r *GuildSettingsProvider) Cleanup() {
+}
+
+func (r *GuildSettingsProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *GuildSettingsProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *GuildSettingsProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to change the guild settings.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *GuildSettingsProvider) settings(m *discordgo.MessageCreate) (string, error) {
+	gs := model.GuildSettings{}
+	settings, err := gs.Get(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	fields := strings.Fields(strings.TrimPrefix(m.Content, GuildSettings))
+	if len(fields) == 0 {
+		return formatGuildSettings(settings), nil
+	}
+
+	for _, field := range fields {
+		kv := strings.SplitN(field, "=", 2)
+		if len(kv) != 2 {
+			return "", fmt.Errorf("could not understand %s, settings look like key=value", field)
+		}
+
+		switch strings.ToLower(kv[0]) {
+		case "boxes":
+			n, err := strconv.ParseInt(kv[1], 10, 64)
+			if err != nil || n < 0 || n > maxBoxesLimit {
+				return "", fmt.Errorf("boxes must be between 0 and %d", maxBoxesLimit)
+			}
+			settings.MaxBoxes = n
+		case "expansion":
+			rs, ok := eq.RulesetByName(kv[1])
+			if !ok {
+				return "", fmt.Errorf("%s is not a supported expansion, choose one of %s", kv[1], eq.ExpansionNames())
+			}
+			settings.Expansion = rs.Expansion
+		default:
+			return "", fmt.Errorf("%s is not a guild setting", kv[0])
+		}
+	}
+
+	if err = settings.Update(r.pool); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if err = eq.SetRuleset(settings.Expansion); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Saved. Members who already have more boxes than the new limit keep them.\n%s", formatGuildSettings(settings)), nil
+}
+
+func formatGuildSettings(settings model.GuildSettings) string {
+	rs := eq.ActiveRuleset()
+
+	var classes []string
+	for _, c := range rs.Classes {
+		classes = append(classes, eq.ClassAbbreviationsMap[c])
+	}
+
+	return fmt.Sprintf("__Guild settings__\nBoxes per member: %d\nExpansion: %s (level cap %d, classes %s)",
+		settings.MaxBoxes,
+		settings.Expansion,
+		rs.MaxLevel,
+		strings.Join(classes, ", "))
+}
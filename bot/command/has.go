@@ -0,0 +1,109 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	hasListSize  = 30
+	minHasSearch = 3
+)
+
+type HasProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewHasProvider(db *pgxpool.Pool) *HasProvider {
+	provider := &HasProvider{pool: db}
+
+	steps := []Step{
+		provider.has,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *HasProvider) Name() string {
+	return Has
+}
+
+func (r *HasProvider) Description() string {
+	return fmt.Sprintf("lists the characters holding an item from their %s uploads e.g. !has sky key", ImportInventory)
+}
+
+func (r *HasProvider) Cleanup() {
+}
+
+func (r *HasProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *HasProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *HasProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *HasProvider) has(m *discordgo.MessageCreate) (string, error) {
+	search := strings.TrimSpace(strings.TrimPrefix(m.Content, Has))
+	if len(search) < minHasSearch {
+		return "", fmt.Errorf("please include at least %d letters of the item name e.g. !has sky key", minHasSearch)
+	}
+
+	inv := model.InventoryItem{}
+	holders, err := inv.FindHolders(r.pool, search, hasListSize+1)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(holders) == 0 {
+		return fmt.Sprintf("No uploaded inventory holds %s.", search), nil
+	}
+
+	more := ""
+	if len(holders) > hasListSize {
+		holders = holders[:hasListSize]
+		more = "\n...and more, try a longer search"
+	}
+
+	var lines []string
+	for _, h := range holders {
+		where := "carried"
+		if eq.IsBankLocation(h.Location) {
+			where = "bank"
+		}
+
+		count := ""
+		if h.Count > 1 {
+			count = fmt.Sprintf(" x%d", h.Count)
+		}
+
+		owner := "unclaimed"
+		if h.CreatedBy != "" {
+			owner = fmt.Sprintf("<@%s>", h.CreatedBy)
+		}
+
+		lines = append(lines, fmt.Sprintf("%s%s - %s (%s) %s, %s as of %s",
+			h.Name,
+			count,
+			h.CharacterName,
+			eq.ClassAbbreviationsMap[h.Class],
+			owner,
+			where,
+			h.ImportedAt.Format("01/02/2006")))
+	}
+
+	return fmt.Sprintf("__Holding %s__\n%s%s", search, strings.Join(lines, "\n"), more), nil
+}
@@ -0,0 +1,269 @@
+package command
+
+import (
+	"bytes"
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	importGuildStateStart   = 0
+	importGuildStateConfirm = 1
+	importGuildStateDone    = 2
+)
+
+// importPreviewSize is how many names of each kind of change are listed before summarising the rest
+const importPreviewSize = 20
+
+type importGuildState struct {
+	creates []model.Character
+	updates []model.Character
+	state   int64
+	userId  string
+	ttl     time.Time
+}
+
+func (r *importGuildState) IsComplete() bool {
+	return r.state == importGuildStateDone
+}
+
+func (r *importGuildState) Step() int64 {
+	return r.state
+}
+
+func (r *importGuildState) TTL() time.Time {
+	return r.ttl
+}
+
+type ImportGuildProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	manifest *Manifest
+}
+
+func NewImportGuildProvider(db *pgxpool.Pool) *ImportGuildProvider {
+	provider := &ImportGuildProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.confirm,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ImportGuildProvider) Name() string {
+	return ImportGuild
+}
+
+func (r *ImportGuildProvider) Description() string {
+	return fmt.Sprintf("creates and updates characters from an attached /outputfile guild dump, members link them with %s. Not available to all users", Claim)
+}
+
+func (r *ImportGuildProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+	})
+}
+
+func (r *ImportGuildProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *ImportGuildProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to import the guild roster.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *ImportGuildProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; ok {
+		return "", nil
+	}
+
+	dat, err := fetchAttachment(m, maxAttachmentSize)
+	if err != nil {
+		return "", err
+	}
+
+	members, err := eq.ParseGuildDump(bytes.NewReader(dat))
+	if err != nil {
+		return "", err
+	}
+
+	if len(members) == 0 {
+		return "", fmt.Errorf("the file did not contain any guild members")
+	}
+
+	c := model.Character{}
+	existing, err := c.GetAll(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	creates, updates, changes, skipped := diffGuildDump(members, existing)
+	if len(creates) == 0 && len(updates) == 0 {
+		if len(skipped) > 0 {
+			return "", fmt.Errorf("nothing can be imported from this file:\n%s", previewList(skipped))
+		}
+		return "The roster is already up to date with this file.", nil
+	}
+
+	r.registry[m.Author.ID] = &importGuildState{
+		creates: creates,
+		updates: updates,
+		state:   importGuildStateConfirm,
+		userId:  m.Author.ID,
+		ttl:     time.Now().Add(commandCacheWindow),
+	}
+
+	var names []string
+	for _, c := range creates {
+		names = append(names, fmt.Sprintf("%s - %d %s %s", c.Name, c.Level, eq.ClassChoiceMap[c.Class], model.CharTypeMap[c.CharacterType]))
+	}
+
+	var skippedString string
+	if len(skipped) > 0 {
+		skippedString = fmt.Sprintf("__Skipped__ - %d\n%s\n", len(skipped), previewList(skipped))
+	}
+
+	return fmt.Sprintf("__New characters__ - %d\n%s\n__Updated characters__ - %d\n%s\n%sUnchanged: %d\n\nImport these changes?\n1. Yes\n2. No",
+		len(creates),
+		previewList(names),
+		len(updates),
+		previewList(changes),
+		skippedString,
+		len(members)-len(creates)-len(updates)-len(skipped)), nil
+}
+
+func (r *ImportGuildProvider) confirm(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*importGuildState)
+		c := model.Character{}
+		if err := c.Import(r.pool, v.creates, v.updates); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		r.Reset(m)
+		return fmt.Sprintf("Imported %d new and %d updated characters. Members can link theirs with **%s name**.", len(v.creates), len(v.updates), Claim), nil
+	case "2":
+		r.Reset(m)
+		return "Discarded the import.", nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *ImportGuildProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+}
+
+// diffGuildDump works out which members are new and which active characters changed, matching on name.
+// retired characters are left alone so a returning member gets a new active character, changes the
+// race and class matrix does not allow are skipped and listed instead
+func diffGuildDump(members []eq.GuildMember, existing []model.Character) ([]model.Character, []model.Character, []string, []string) {
+	byName := make(map[string]model.Character)
+	for _, c := range existing {
+		if c.Retired {
+			continue
+		}
+		byName[strings.ToLower(c.Name)] = c
+	}
+
+	var (
+		creates []model.Character
+		updates []model.Character
+		changes []string
+		skipped []string
+	)
+
+	seen := make(map[string]bool)
+	for _, gm := range members {
+		key := strings.ToLower(gm.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		c, ok := byName[key]
+		if !ok {
+			charType := int64(model.TypeMain)
+			if gm.Alt {
+				charType = model.TypeAlt
+			}
+			creates = append(creates, model.Character{
+				Name:          gm.Name,
+				Class:         gm.Class,
+				Level:         gm.Level,
+				CharacterType: charType,
+				Rank:          gm.Rank,
+				Notes:         gm.Notes,
+				LastOn:        gm.LastOn,
+			})
+			continue
+		}
+
+		var diff []string
+		if c.Level != gm.Level {
+			diff = append(diff, fmt.Sprintf("level %d -> %d", c.Level, gm.Level))
+		}
+		if c.Class != gm.Class {
+			if !eq.ValidRace(gm.Class, c.Race) {
+				skipped = append(skipped, fmt.Sprintf("%s: a %s cannot be a %s", c.Name, eq.RaceChoiceMap[c.Race], eq.ClassChoiceMap[gm.Class]))
+				continue
+			}
+			diff = append(diff, fmt.Sprintf("class %s -> %s", eq.ClassChoiceMap[c.Class], eq.ClassChoiceMap[gm.Class]))
+		}
+		if c.Rank != gm.Rank {
+			diff = append(diff, fmt.Sprintf("rank %s", gm.Rank))
+		}
+		if c.Notes != gm.Notes {
+			diff = append(diff, "notes")
+		}
+
+		if len(diff) == 0 {
+			continue
+		}
+
+		c.Level = gm.Level
+		c.Class = gm.Class
+		c.Rank = gm.Rank
+		c.Notes = gm.Notes
+		c.LastOn = gm.LastOn
+		updates = append(updates, c)
+		changes = append(changes, fmt.Sprintf("%s: %s", c.Name, strings.Join(diff, ", ")))
+	}
+
+	return creates, updates, changes, skipped
+}
+
+func previewList(lines []string) string {
+	if len(lines) > importPreviewSize {
+		more := len(lines) - importPreviewSize
+		lines = append(lines[:importPreviewSize:importPreviewSize], fmt.Sprintf("... and %d more", more))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,122 @@
+package command
+
+import (
+	"bytes"
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type ImportInventoryProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewImportInventoryProvider(db *pgxpool.Pool) *ImportInventoryProvider {
+	provider := &ImportInventoryProvider{pool: db}
+
+	steps := []Step{
+		provider.importInventory,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ImportInventoryProvider) Name() string {
+	return ImportInventory
+}
+
+func (r *ImportInventoryProvider) Description() string {
+	return fmt.Sprintf("stores the inventory and bank of one of your characters from an attached /outputfile inventory file, searchable with %s", Has)
+}
+
+func (r *ImportInventoryProvider) Cleanup() {
+}
+
+func (r *ImportInventoryProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *ImportInventoryProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *ImportInventoryProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *ImportInventoryProvider) importInventory(m *discordgo.MessageCreate) (string, error) {
+	if len(m.Attachments) == 0 {
+		return "", errors.New("please attach the Name-Inventory.txt file written by /outputfile inventory to the same message as the command")
+	}
+
+	// the character can be named in the command when the file was renamed
+	name := strings.TrimSpace(strings.TrimPrefix(m.Content, ImportInventory))
+	if name == "" {
+		owner, ok := eq.InventoryOwnerFromFilename(m.Attachments[0].Filename)
+		if !ok {
+			return "", fmt.Errorf("could not tell which character %s belongs to, please include the name e.g. %s Clericbot", m.Attachments[0].Filename, ImportInventory)
+		}
+		name = owner
+	}
+
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	var toon *model.Character
+	for i := range toons {
+		if strings.EqualFold(toons[i].Name, name) {
+			toon = &toons[i]
+		}
+	}
+
+	if toon == nil {
+		return "", fmt.Errorf("%s is not one of your characters", name)
+	}
+
+	dat, err := fetchAttachment(m, maxAttachmentSize)
+	if err != nil {
+		return "", err
+	}
+
+	parsed, err := eq.ParseInventory(bytes.NewReader(dat))
+	if err != nil {
+		return "", err
+	}
+
+	var (
+		items []model.InventoryItem
+		bank  int
+	)
+	for _, item := range parsed {
+		if item.InBank() {
+			bank++
+		}
+		items = append(items, model.InventoryItem{
+			Location: item.Location,
+			Name:     item.Name,
+			ItemId:   item.ItemId,
+			Count:    item.Count,
+			Slots:    item.Slots,
+		})
+	}
+
+	inv := model.InventoryItem{}
+	if err = inv.ReplaceForCharacter(r.pool, toon.Id, items); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("Stored %d items for %s, %d of them in the bank.", len(items), toon.Name, bank), nil
+}
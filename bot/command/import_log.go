@@ -0,0 +1,386 @@
+package command
+
+import (
+	"bytes"
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/bot/eqlog"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	importLogStateStart   = 0
+	importLogStateEvent   = 1
+	importLogStateConfirm = 2
+	importLogStateDone    = 3
+)
+
+// logWindowSlack widens an events time window to catch people forming up early and looting late
+const logWindowSlack = 30 * time.Minute
+
+type importLogState struct {
+	lines   []eqlog.Line
+	event   model.Event
+	kills   []model.EventKill
+	loot    []model.EventLoot
+	present []int64
+	levels  map[int64]int64
+	state   int64
+	userId  string
+	ttl     time.Time
+}
+
+func (r *importLogState) IsComplete() bool {
+	return r.state == importLogStateDone
+}
+
+func (r *importLogState) Step() int64 {
+	return r.state
+}
+
+func (r *importLogState) TTL() time.Time {
+	return r.ttl
+}
+
+type ImportLogProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	eventReg map[string]map[int]model.Event
+	manifest *Manifest
+}
+
+func NewImportLogProvider(db *pgxpool.Pool) *ImportLogProvider {
+	provider := &ImportLogProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		eventReg: make(map[string]map[int]model.Event),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.event,
+		provider.confirm,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ImportLogProvider) Name() string {
+	return ImportLog
+}
+
+func (r *ImportLogProvider) Description() string {
+	return "records kills, loot and attendance for an event from an attached eqlog file, add the logs time zone if it is not the bots e.g. !import-log EST. Not available to all users"
+}
+
+func (r *ImportLogProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.eventReg, k)
+	})
+}
+
+func (r *ImportLogProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *ImportLogProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to import log files.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *ImportLogProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; ok {
+		return "", nil
+	}
+
+	loc := time.Local
+	if args := strings.Fields(strings.TrimPrefix(m.Content, ImportLog)); len(args) > 0 {
+		l, err := time.LoadLocation(args[0])
+		if err != nil {
+			return "", fmt.Errorf("%s is not a time zone I know, try something like EST or America/Chicago", args[0])
+		}
+		loc = l
+	}
+
+	if len(m.Attachments) == 0 {
+		return "", errors.New("please attach the log file to the same message as the command")
+	}
+
+	owner, _, ok := eqlog.OwnerFromFilename(m.Attachments[0].Filename)
+	if !ok {
+		return "", errors.New("log files must keep their original name e.g. eqlog_Soandso_server.txt")
+	}
+
+	dat, err := fetchAttachment(m, maxLogSize)
+	if err != nil {
+		return "", err
+	}
+
+	lines, err := eqlog.Parse(bytes.NewReader(dat), owner, loc)
+	if err != nil {
+		return "", err
+	}
+
+	e := model.Event{}
+	events, err := e.GetWhereStatus(r.pool, []int64{model.EventStatusStarted})
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	recent, err := e.GetHistory(r.pool, model.EventHistoryFilter{Limit: importRaidRecent})
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+	events = append(events, recent...)
+
+	if len(events) == 0 {
+		return "", errors.New("there are no started or completed events to import a log for")
+	}
+
+	r.registry[m.Author.ID] = &importLogState{
+		lines:  lines,
+		state:  importLogStateEvent,
+		userId: m.Author.ID,
+		ttl:    time.Now().Add(commandCacheWindow),
+	}
+
+	r.eventReg[m.Author.ID] = make(map[int]model.Event)
+
+	var eventString []string
+	for i, e := range events {
+		r.eventReg[m.Author.ID][i] = e
+		eventString = append(eventString, fmt.Sprintf("%d. %s %s (%s)", i, e.Title, e.EventTime.Format(time.RFC822), model.EventStatusMap[e.Status]))
+	}
+
+	return fmt.Sprintf("Which event is %ss log for?\n%s", owner, strings.Join(eventString, "\n")), nil
+}
+
+func (r *ImportLogProvider) event(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	c := model.Character{}
+	toons, err := c.GetAll(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	byName := make(map[string]model.Character)
+	players := make(map[string]bool)
+	for _, t := range toons {
+		players[strings.ToLower(t.Name)] = true
+		if existing, ok := byName[strings.ToLower(t.Name)]; ok && !existing.Retired {
+			continue
+		}
+		byName[strings.ToLower(t.Name)] = t
+	}
+
+	v := r.registry[m.Author.ID].(*importLogState)
+	v.event = e
+	v.kills = nil
+	v.loot = nil
+	v.present = nil
+
+	var levelUps []string
+	v.levels, levelUps = logLevelUps(v.lines, byName)
+
+	from := e.EventTime.Add(-logWindowSlack)
+	to := e.EndTime().Add(logWindowSlack)
+
+	var (
+		kills   []string
+		loot    []string
+		names   []string
+		unknown []string
+		sighted []string
+		seen    = make(map[string]bool)
+		who     = make(map[string]model.Character)
+	)
+
+	for _, l := range v.lines {
+		if l.Time.Before(from) || l.Time.After(to) {
+			continue
+		}
+
+		switch l.Kind {
+		case eqlog.KindSlain:
+			// members dying are not kills
+			if !l.IsKill(players) {
+				continue
+			}
+			v.kills = append(v.kills, model.EventKill{EventId: e.Id, Mob: l.Target, KilledBy: l.Name, KilledAt: l.Time})
+			kills = append(kills, fmt.Sprintf("%s by %s", l.Target, l.Name))
+			continue
+		case eqlog.KindLoot:
+			row := model.EventLoot{EventId: e.Id, CharacterName: l.Name, Item: l.Target, LootedAt: l.Time}
+			if t, ok := byName[strings.ToLower(l.Name)]; ok {
+				row.CharacterId = &t.Id
+			}
+			v.loot = append(v.loot, row)
+			loot = append(loot, fmt.Sprintf("%s - %s", l.Name, l.Target))
+		case eqlog.KindWho:
+			// /who all lists players in every zone so a sighting is reported but never counts as attending
+			if t, ok := byName[strings.ToLower(l.Name)]; ok {
+				who[strings.ToLower(l.Name)] = t
+			}
+			continue
+		case eqlog.KindRaidJoin:
+		default:
+			continue
+		}
+
+		key := strings.ToLower(l.Name)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		t, ok := byName[key]
+		if !ok {
+			if l.Kind == eqlog.KindRaidJoin {
+				unknown = append(unknown, l.Name)
+			}
+			continue
+		}
+
+		v.present = append(v.present, t.Id)
+		names = append(names, t.Name)
+	}
+
+	for key, t := range who {
+		if !seen[key] {
+			sighted = append(sighted, t.Name)
+		}
+	}
+	sort.Strings(sighted)
+
+	if len(v.kills) == 0 && len(v.loot) == 0 && len(v.present) == 0 {
+		r.Reset(m)
+		return "", fmt.Errorf("the log has nothing between %s and %s for %s", from.Format(time.RFC822), to.Format(time.RFC822), e.Title)
+	}
+
+	v.state = importLogStateConfirm
+
+	return fmt.Sprintf(`__%s__
+Kills - %d
+%s
+Loot - %d
+%s
+Present - %d: %s
+Unknown raid members - %d: %s
+Seen in /who but not in the raid, not counted - %d: %s
+Level ups - %d: %s
+
+Record this against the event?
+1. Yes
+2. No`,
+		e.Title,
+		len(kills), previewList(kills),
+		len(loot), previewList(loot),
+		len(names), strings.Join(names, ", "),
+		len(unknown), strings.Join(unknown, ", "),
+		len(sighted), strings.Join(sighted, ", "),
+		len(levelUps), strings.Join(levelUps, ", ")), nil
+}
+
+func (r *ImportLogProvider) confirm(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*importLogState)
+		if err := model.SaveEventLog(r.pool, v.kills, v.loot); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(v.levels) > 0 {
+			c := model.Character{}
+			if err := c.RaiseLevels(r.pool, v.levels); err != nil {
+				log.Println(err.Error())
+				return "", ErrorInternalError
+			}
+		}
+
+		if len(v.present) > 0 {
+			a := model.Attendance{}
+			if err := a.MarkAttended(r.pool, v.event.Id, v.present); err != nil {
+				log.Println(err.Error())
+				return "", ErrorInternalError
+			}
+		}
+
+		r.Reset(m)
+		return fmt.Sprintf("Recorded the log against %s.", v.event.Title), nil
+	case "2":
+		r.Reset(m)
+		return "Discarded the log.", nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+// logLevelUps finds characters whose highest /who level in the log is above their recorded level,
+// the whole log is used since a level seen outside the event is still current
+func logLevelUps(lines []eqlog.Line, byName map[string]model.Character) (map[int64]int64, []string) {
+	levels := make(map[int64]int64)
+	for _, l := range lines {
+		if l.Kind != eqlog.KindWho || l.Level == 0 {
+			continue
+		}
+
+		t, ok := byName[strings.ToLower(l.Name)]
+		if !ok || t.Retired {
+			continue
+		}
+
+		if l.Level > t.Level && l.Level > levels[t.Id] && l.Level <= eq.MaxLevel() {
+			levels[t.Id] = l.Level
+		}
+	}
+
+	var levelUps []string
+	for _, t := range byName {
+		if level, ok := levels[t.Id]; ok {
+			levelUps = append(levelUps, fmt.Sprintf("%s %d to %d", t.Name, t.Level, level))
+		}
+	}
+	sort.Strings(levelUps)
+
+	return levels, levelUps
+}
+
+func (r *ImportLogProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.eventReg, m.Author.ID)
+}
@@ -0,0 +1,292 @@
+package command
+
+import (
+	"bytes"
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	importRaidStateStart   = 0
+	importRaidStateEvent   = 1
+	importRaidStateConfirm = 2
+	importRaidStateDone    = 3
+)
+
+// importRaidRecent is how many completed events are offered besides the ones in progress
+const importRaidRecent = 5
+
+type importRaidState struct {
+	members []eq.RaidMember
+	event   model.Event
+	present []int64
+	state   int64
+	userId  string
+	ttl     time.Time
+}
+
+func (r *importRaidState) IsComplete() bool {
+	return r.state == importRaidStateDone
+}
+
+func (r *importRaidState) Step() int64 {
+	return r.state
+}
+
+func (r *importRaidState) TTL() time.Time {
+	return r.ttl
+}
+
+type ImportRaidProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	eventReg map[string]map[int]model.Event
+	manifest *Manifest
+}
+
+func NewImportRaidProvider(db *pgxpool.Pool) *ImportRaidProvider {
+	provider := &ImportRaidProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+		eventReg: make(map[string]map[int]model.Event),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.event,
+		provider.confirm,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ImportRaidProvider) Name() string {
+	return ImportRaid
+}
+
+func (r *ImportRaidProvider) Description() string {
+	return "records who attended an event from an attached /outputfile raid dump, not available to all users"
+}
+
+func (r *ImportRaidProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.eventReg, k)
+	})
+}
+
+func (r *ImportRaidProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *ImportRaidProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to import raid attendance.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *ImportRaidProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; ok {
+		return "", nil
+	}
+
+	dat, err := fetchAttachment(m, maxAttachmentSize)
+	if err != nil {
+		return "", err
+	}
+
+	members, err := eq.ParseRaidDump(bytes.NewReader(dat))
+	if err != nil {
+		return "", err
+	}
+
+	if len(members) == 0 {
+		return "", errors.New("the file did not contain anyone in the raid")
+	}
+
+	e := model.Event{}
+	events, err := e.GetWhereStatus(r.pool, []int64{model.EventStatusStarted})
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	recent, err := e.GetHistory(r.pool, model.EventHistoryFilter{Limit: importRaidRecent})
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+	events = append(events, recent...)
+
+	if len(events) == 0 {
+		return "", errors.New("there are no started or completed events to record attendance for")
+	}
+
+	r.registry[m.Author.ID] = &importRaidState{
+		members: members,
+		state:   importRaidStateEvent,
+		userId:  m.Author.ID,
+		ttl:     time.Now().Add(commandCacheWindow),
+	}
+
+	r.eventReg[m.Author.ID] = make(map[int]model.Event)
+
+	var eventString []string
+	for i, e := range events {
+		r.eventReg[m.Author.ID][i] = e
+		eventString = append(eventString, fmt.Sprintf("%d. %s %s (%s)", i, e.Title, e.EventTime.Format(time.RFC822), model.EventStatusMap[e.Status]))
+	}
+
+	return fmt.Sprintf("Which event is this raid dump for?\n%s", strings.Join(eventString, "\n")), nil
+}
+
+func (r *ImportRaidProvider) event(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	e, ok := r.eventReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid event selection")
+	}
+
+	v := r.registry[m.Author.ID].(*importRaidState)
+
+	c := model.Character{}
+	toons, err := c.GetAll(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	a := model.Attendance{}
+	signedUp, err := a.GetAttendees(r.pool, e.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	report := reconcileRaid(v.members, toons, signedUp)
+
+	if len(report.present) == 0 {
+		r.Reset(m)
+		return "", fmt.Errorf("none of the names in the raid dump match a registered character, unknown names: %s", strings.Join(report.unknown, ", "))
+	}
+
+	v.event = e
+	v.present = report.present
+	v.state = importRaidStateConfirm
+
+	return fmt.Sprintf(`__%s__
+Present: %d
+Unknown names - %d: %s
+No-shows - %d: %s
+Walk-ins - %d: %s
+
+Record this attendance?
+1. Yes
+2. No`,
+		e.Title,
+		len(report.present),
+		len(report.unknown), strings.Join(report.unknown, ", "),
+		len(report.noShows), strings.Join(report.noShows, ", "),
+		len(report.walkIns), strings.Join(report.walkIns, ", ")), nil
+}
+
+func (r *ImportRaidProvider) confirm(m *discordgo.MessageCreate) (string, error) {
+	switch m.Content {
+	case "1":
+		v := r.registry[m.Author.ID].(*importRaidState)
+		a := model.Attendance{}
+		if err := a.RecordPresence(r.pool, v.event.Id, v.present); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		r.Reset(m)
+		return fmt.Sprintf("Recorded %d characters as attending %s.", len(v.present), v.event.Title), nil
+	case "2":
+		r.Reset(m)
+		return "Discarded the raid dump.", nil
+	default:
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *ImportRaidProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.eventReg, m.Author.ID)
+}
+
+type raidReport struct {
+	present []int64
+	unknown []string
+	noShows []string
+	walkIns []string
+}
+
+// reconcileRaid compares who was in the raid with who signed up
+func reconcileRaid(members []eq.RaidMember, toons []model.Character, signedUp []model.Character) raidReport {
+	byName := make(map[string]model.Character)
+	for _, t := range toons {
+		key := strings.ToLower(t.Name)
+		// prefer an active character if a retired one shares the name
+		if existing, ok := byName[key]; ok && !existing.Retired {
+			continue
+		}
+		byName[key] = t
+	}
+
+	expected := make(map[int64]bool)
+	for _, t := range signedUp {
+		expected[t.Id] = true
+	}
+
+	report := raidReport{}
+	here := make(map[int64]bool)
+	for _, rm := range members {
+		t, ok := byName[strings.ToLower(rm.Name)]
+		if !ok {
+			report.unknown = append(report.unknown, rm.Name)
+			continue
+		}
+
+		if here[t.Id] {
+			continue
+		}
+		here[t.Id] = true
+		report.present = append(report.present, t.Id)
+
+		if !expected[t.Id] {
+			report.walkIns = append(report.walkIns, t.Name)
+		}
+	}
+
+	for _, t := range signedUp {
+		if !here[t.Id] {
+			report.noShows = append(report.noShows, t.Name)
+		}
+	}
+
+	return report
+}
@@ -65,7 +65,7 @@ func (p *MyCharactersProvider) list(m *discordgo.MessageCreate) (string, error)
 	var charStrings []string
 
 	for i, k := range toons {
-		charStrings = append(charStrings, fmt.Sprintf("%d. %s - %d %s %s", i+1, k.Name, k.Level, eq.ClassChoiceMap[k.Class], model.CharTypeMap[k.CharacterType]))
+		charStrings = append(charStrings, fmt.Sprintf("%d. %s - %d %s %s (%d AA)", i+1, k.Name, k.Level, eq.ClassChoiceMap[k.Class], model.CharTypeMap[k.CharacterType], k.AA))
 	}
 
 	if len(charStrings) == 0 {
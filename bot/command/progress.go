@@ -0,0 +1,101 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// progressTimelineSize is how many of the most recent changes are shown
+const progressTimelineSize = 20
+
+type ProgressProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewProgressProvider(db *pgxpool.Pool) *ProgressProvider {
+	provider := &ProgressProvider{pool: db}
+
+	steps := []Step{
+		provider.progress,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ProgressProvider) Name() string {
+	return Progress
+}
+
+func (r *ProgressProvider) Description() string {
+	return "shows every level and AA change of a character e.g. !progress Clericbot"
+}
+
+func (r *ProgressProvider) Cleanup() {
+}
+
+func (r *ProgressProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *ProgressProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *ProgressProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *ProgressProvider) progress(m *discordgo.MessageCreate) (string, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(m.Content, Progress))
+	if name == "" {
+		return "", errors.New("please include the character name e.g. !progress Clericbot")
+	}
+
+	c := model.Character{}
+	toon, ok, err := c.GetByName(r.pool, name)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if !ok {
+		return "", fmt.Errorf("there is no character called %s", name)
+	}
+
+	cp := model.CharacterProgress{}
+	history, err := cp.GetForCharacter(r.pool, toon.Id)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(history) == 0 {
+		return fmt.Sprintf("%s has no recorded progress.", toon.Name), nil
+	}
+
+	var lines []string
+	for i, p := range history {
+		change := "first recorded"
+		if i > 0 {
+			change = fmt.Sprintf("%+d levels, %+d AA", p.Level-history[i-1].Level, p.AA-history[i-1].AA)
+		}
+		lines = append(lines, fmt.Sprintf("%s - level %d, %d AA (%s)", p.RecordedAt.Format("01/02/2006"), p.Level, p.AA, change))
+	}
+
+	earlier := ""
+	if len(lines) > progressTimelineSize {
+		earlier = fmt.Sprintf("...%d earlier changes\n", len(lines)-progressTimelineSize)
+		lines = lines[len(lines)-progressTimelineSize:]
+	}
+
+	return fmt.Sprintf("__%s - %d %s__\n%s%s", toon.Name, toon.Level, eq.ClassChoiceMap[toon.Class], earlier, strings.Join(lines, "\n")), nil
+}
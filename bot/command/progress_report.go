@@ -0,0 +1,117 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	progressReportDays  = 30
+	progressReportLimit = 15
+)
+
+type ProgressReportProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewProgressReportProvider(db *pgxpool.Pool) *ProgressReportProvider {
+	provider := &ProgressReportProvider{pool: db}
+
+	steps := []Step{
+		provider.report,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ProgressReportProvider) Name() string {
+	return ProgressReport
+}
+
+func (r *ProgressReportProvider) Description() string {
+	return fmt.Sprintf("lists the biggest movers and stalled mains over a number of days e.g. !progress-report 14, defaults to %d. Not available to all users", progressReportDays)
+}
+
+func (r *ProgressReportProvider) Cleanup() {
+}
+
+func (r *ProgressReportProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *ProgressReportProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *ProgressReportProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to view the progress report.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *ProgressReportProvider) report(m *discordgo.MessageCreate) (string, error) {
+	days := progressReportDays
+	if arg := strings.TrimSpace(strings.TrimPrefix(m.Content, ProgressReport)); arg != "" {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 {
+			return "", errors.New("the period must be a number of days greater than 0")
+		}
+		days = n
+	}
+
+	since := time.Now().AddDate(0, 0, -days)
+
+	cp := model.CharacterProgress{}
+	movers, err := cp.GetMovers(r.pool, since, progressReportLimit)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	stalled, err := cp.GetStalled(r.pool, since, eq.MaxLevel())
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	moverString := "No one has gained a level or AA."
+	if len(movers) > 0 {
+		var lines []string
+		for i, mv := range movers {
+			lines = append(lines, fmt.Sprintf("%d. %s - %d %s, %+d levels, %+d AA", i+1, mv.Name, mv.Level, eq.ClassAbbreviationsMap[mv.Class], mv.LevelGain, mv.AAGain))
+		}
+		moverString = strings.Join(lines, "\n")
+	}
+
+	stalledString := "none"
+	if len(stalled) > 0 {
+		var names []string
+		for _, t := range stalled {
+			names = append(names, fmt.Sprintf("%s (%d %s)", t.Name, t.Level, eq.ClassAbbreviationsMap[t.Class]))
+		}
+		stalledString = strings.Join(names, ", ")
+	}
+
+	return fmt.Sprintf("__Biggest movers in the last %d days__\n%s\n\n__Stalled mains below level %d__ - %d: %s",
+		days,
+		moverString,
+		eq.MaxLevel(),
+		len(stalled),
+		stalledString), nil
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,17 +17,21 @@ const (
 	regStateStart = 0
 	regStateName  = 1
 	regStateClass = 2
-	regStateLevel = 3
-	regStateMata  = 4
-	regStateDone  = 5
-	regStateSaved = 6
+	regStateRace  = 3
+	regStateLevel = 4
+	regStateAA    = 5
+	regStateMata  = 6
+	regStateDone  = 7
+	regStateSaved = 8
 )
 
 type registrationState struct {
 	state    int64
 	name     string
 	class    int64
+	race     int64
 	level    int64
+	aa       int64
 	userId   string
 	charType int64
 	ttl      time.Time
@@ -36,8 +41,9 @@ func (r *registrationState) toModel() *model.Character {
 	return &model.Character{
 		Name:          r.name,
 		Class:         r.class,
+		Race:          r.race,
 		Level:         r.level,
-		AA:            0,
+		AA:            r.aa,
 		CharacterType: r.charType,
 		CreatedBy:     r.userId,
 	}
@@ -71,7 +77,9 @@ func NewRegistrationProvider(db *pgxpool.Pool) *RegistrationProvider {
 		provider.start,
 		provider.name,
 		provider.class,
+		provider.race,
 		provider.level,
+		provider.aa,
 		provider.meta,
 		provider.done,
 	}
@@ -123,8 +131,13 @@ func (r *RegistrationProvider) start(m *discordgo.MessageCreate) (string, error)
 }
 
 func (r *RegistrationProvider) name(m *discordgo.MessageCreate) (string, error) {
+	name, err := checkNameAvailable(r.pool, m.Content, 0)
+	if err != nil {
+		return "", err
+	}
+
 	v := r.registry[m.Author.ID].(*registrationState)
-	v.name = m.Content
+	v.name = name
 	v.state = regStateClass
 	r.registry[m.Author.ID] = v
 
@@ -138,12 +151,27 @@ func (r *RegistrationProvider) class(m *discordgo.MessageCreate) (string, error)
 		return "", ErrorInvalidInput
 	}
 
-	if _, ok := eq.ClassChoiceMap[classId]; !ok {
+	if !eq.ActiveRuleset().HasClass(classId) {
 		return "", errors.New("invalid class choice, please try again and pick the number next the corresponding class")
 	}
 
 	v := r.registry[m.Author.ID].(*registrationState)
 	v.class = classId
+	v.state = regStateRace
+	r.registry[m.Author.ID] = v
+
+	return fmt.Sprintf("What is your race? Respond with the number that corresponds. \n%s", eq.RaceChoiceString(classId)), nil
+}
+
+func (r *RegistrationProvider) race(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*registrationState)
+
+	raceId, err := parseRace(m.Content, v.class)
+	if err != nil {
+		return "", err
+	}
+
+	v.race = raceId
 	v.state = regStateLevel
 	r.registry[m.Author.ID] = v
 
@@ -156,16 +184,37 @@ func (r *RegistrationProvider) level(m *discordgo.MessageCreate) (string, error)
 		return "", ErrorInvalidInput
 	}
 
-	if i > eq.MaxLevel || i < 0 {
-		return "", errors.New(fmt.Sprintf("a characters level must be between 0 and %d", eq.MaxLevel))
+	if i > eq.MaxLevel() || i < 0 {
+		return "", errors.New(fmt.Sprintf("a characters level must be between 0 and %d", eq.MaxLevel()))
 	}
 
 	v := r.registry[m.Author.ID].(*registrationState)
 	v.level = i
+	v.state = regStateAA
+	r.registry[m.Author.ID] = v
+
+	return "How many AA points do you have? Respond with 0 if you have none.", nil
+}
+
+func (r *RegistrationProvider) aa(m *discordgo.MessageCreate) (string, error) {
+	i, err := parseAA(m.Content)
+	if err != nil {
+		return "", err
+	}
+
+	gs := model.GuildSettings{}
+	settings, err := gs.Get(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	v := r.registry[m.Author.ID].(*registrationState)
+	v.aa = i
 	v.state = regStateMata
 	r.registry[m.Author.ID] = v
 
-	return "You can only have one 'main' and one 'box', all other characters must be registered as alts.\n\nHow would you describe this character?\n1. Box\n2. Main\n3. Alt", nil
+	return fmt.Sprintf("Each discord account can have one main and up to %d box characters, all other characters must be registered as alts.\n\nHow would you describe this character?\n1. Box\n2. Main\n3. Alt", settings.MaxBoxes), nil
 }
 
 func (r *RegistrationProvider) meta(m *discordgo.MessageCreate) (string, error) {
@@ -178,25 +227,8 @@ func (r *RegistrationProvider) meta(m *discordgo.MessageCreate) (string, error)
 		return "", ErrorInvalidInput
 	}
 
-	c := model.Character{}
-	toons, err := c.GetByOwner(r.pool, m.Author.ID)
-	if err != nil {
-		return "", ErrorInternalError
-	}
-
-	switch typeId {
-	case model.TypeMain, model.TypeBox:
-		for _, k := range toons {
-			if typeId == model.TypeMain {
-				if k.CharacterType == model.TypeMain {
-					return "You already have a main character registered, please choose alt, or box if you wish to register this character as a valid raid box.", nil
-				}
-			} else {
-				if k.CharacterType == model.TypeBox {
-					return "You already have an box character registered, please choose main, or alt.", nil
-				}
-			}
-		}
+	if err = checkTypeLimit(r.pool, m.Author.ID, 0, typeId); err != nil {
+		return "", err
 	}
 
 	v := r.registry[m.Author.ID].(*registrationState)
@@ -204,10 +236,12 @@ func (r *RegistrationProvider) meta(m *discordgo.MessageCreate) (string, error)
 	v.state = regStateDone
 	r.registry[m.Author.ID] = v
 
-	return fmt.Sprintf("Is this all correct?\nName: %s\nClass: %s\nLevel: %d\nType:%s\n\n1. Yes\n2. No",
+	return fmt.Sprintf("Is this all correct?\nName: %s\nClass: %s\nRace: %s\nLevel: %d\nAA: %d\nType:%s\n\n1. Yes\n2. No",
 		v.name,
 		eq.ClassChoiceMap[v.class],
+		eq.RaceChoiceMap[v.race],
 		v.level,
+		v.aa,
 		model.CharTypeMap[v.charType]), nil
 }
 
@@ -217,7 +251,18 @@ func (r *RegistrationProvider) done(m *discordgo.MessageCreate) (string, error)
 		dat := r.registry[m.Author.ID].(*registrationState)
 		err := dat.toModel().Save(r.pool)
 
+		if errors.Is(err, model.ErrNameTaken) {
+			r.Reset(m)
+			return "", fmt.Errorf("%s was registered by someone else while you were registering, type **%s %s** if it is yours", dat.name, CharacterDispute, dat.name)
+		}
+
+		if errors.Is(err, model.ErrMainTaken) || errors.Is(err, model.ErrBoxLimit) {
+			r.Reset(m)
+			return "", fmt.Errorf("you registered another %s while this registration was open, please start again", strings.ToLower(model.CharTypeMap[dat.charType]))
+		}
+
 		if err != nil {
+			log.Println(err.Error())
 			return "", ErrorInternalError
 		}
 
@@ -236,6 +281,84 @@ func (r *RegistrationProvider) done(m *discordgo.MessageCreate) (string, error)
 	}
 }
 
+// checkTypeLimit makes sure a character of the given type keeps its account within one main and the guilds box limit,
+// limits are per discord account like the database enforces them, characterId is the character changing type and is 0 for new characters
+func checkTypeLimit(db *pgxpool.Pool, userId string, characterId int64, typeId int64) error {
+	if typeId == model.TypeAlt {
+		return nil
+	}
+
+	c := model.Character{}
+	toons, err := c.GetByAccount(db, userId)
+	if err != nil {
+		log.Println(err.Error())
+		return ErrorInternalError
+	}
+
+	var boxes []string
+	for _, t := range toons {
+		if t.Id == characterId || t.CharacterType != typeId {
+			continue
+		}
+
+		if typeId == model.TypeMain {
+			return fmt.Errorf("%s is already your main, please choose alt or box. You can promote a character to main with **%s**", t.Name, CharacterEdit)
+		}
+		boxes = append(boxes, t.Name)
+	}
+
+	if typeId == model.TypeBox {
+		gs := model.GuildSettings{}
+		settings, err := gs.Get(db)
+		if err != nil {
+			log.Println(err.Error())
+			return ErrorInternalError
+		}
+
+		if int64(len(boxes)) >= settings.MaxBoxes {
+			return fmt.Errorf("the guild allows %d box characters each and you already have %s, please choose main or alt", settings.MaxBoxes, strings.Join(boxes, ", "))
+		}
+	}
+
+	return nil
+}
+
+// parseRace reads a race choice and checks it can be played with the class under the active ruleset
+func parseRace(input string, class int64) (int64, error) {
+	raceId, err := strconv.ParseInt(input, 10, 64)
+	if err != nil {
+		return 0, ErrorInvalidInput
+	}
+
+	if _, ok := eq.RaceChoiceMap[raceId]; !ok || raceId == eq.RaceUnknown || !eq.ActiveRuleset().HasRace(raceId) {
+		return 0, errors.New("invalid race choice, please try again and pick the number next the corresponding race")
+	}
+
+	if !eq.ValidRace(class, raceId) {
+		return 0, fmt.Errorf("a %s cannot be a %s, please pick one of the listed races", eq.RaceChoiceMap[raceId], eq.ClassChoiceMap[class])
+	}
+
+	return raceId, nil
+}
+
+// parseAA reads an AA count and checks it against the configured maximum
+func parseAA(input string) (int64, error) {
+	i, err := strconv.ParseInt(input, 10, 64)
+	if err != nil {
+		return 0, ErrorInvalidInput
+	}
+
+	if i > 0 && eq.MaxAA() == 0 {
+		return 0, fmt.Errorf("there are no AA in %s, respond with 0", eq.ActiveRuleset().Expansion)
+	}
+
+	if i > eq.MaxAA() || i < 0 {
+		return 0, fmt.Errorf("a characters AA must be between 0 and %d", eq.MaxAA())
+	}
+
+	return i, nil
+}
+
 func (r *RegistrationProvider) Reset(m *discordgo.MessageCreate) {
 	delete(r.registry, m.Author.ID)
 }
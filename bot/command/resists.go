@@ -0,0 +1,118 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type ResistsProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewResistsProvider(db *pgxpool.Pool) *ResistsProvider {
+	provider := &ResistsProvider{pool: db}
+
+	steps := []Step{
+		provider.resists,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ResistsProvider) Name() string {
+	return Resists
+}
+
+func (r *ResistsProvider) Description() string {
+	return "records the resists of one of your characters e.g. !resists Clericbot FR:150 CR:120 MR:100 PR:90 DR:90, on its own lists them"
+}
+
+func (r *ResistsProvider) Cleanup() {
+}
+
+func (r *ResistsProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *ResistsProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *ResistsProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *ResistsProvider) resists(m *discordgo.MessageCreate) (string, error) {
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(toons) == 0 {
+		return "", fmt.Errorf("you have no characters registered, please type **%s** to add one", Register)
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(m.Content, Resists)), " ", 2)
+	if fields[0] == "" {
+		return r.list(toons)
+	}
+
+	var toon *model.Character
+	for i := range toons {
+		if strings.EqualFold(toons[i].Name, fields[0]) {
+			toon = &toons[i]
+		}
+	}
+
+	if toon == nil {
+		return "", fmt.Errorf("%s is not one of your characters", fields[0])
+	}
+
+	if len(fields) < 2 {
+		return "", fmt.Errorf("please include the resists to record e.g. %s %s FR:150 CR:120", Resists, toon.Name)
+	}
+
+	resists, err := eq.ParseResists(fields[1])
+	if err != nil {
+		return "", err
+	}
+
+	cr := model.CharacterResist{}
+	if err = cr.SetForCharacter(r.pool, toon.Id, resists); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("Recorded %s for %s.", eq.FormatResists(resists), toon.Name), nil
+}
+
+func (r *ResistsProvider) list(toons []model.Character) (string, error) {
+	var ids []int64
+	for _, t := range toons {
+		ids = append(ids, t.Id)
+	}
+
+	cr := model.CharacterResist{}
+	resists, err := cr.GetForCharacters(r.pool, ids)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	var lines []string
+	for _, t := range toons {
+		lines = append(lines, fmt.Sprintf("%s - %s", t.Name, eq.FormatResists(resists[t.Id])))
+	}
+
+	return fmt.Sprintf("__Recorded resists__\n%s", strings.Join(lines, "\n")), nil
+}
@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"github.com/bwmarrin/discordgo"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"log"
 	"sort"
 	"strconv"
 	"strings"
@@ -146,38 +147,107 @@ func (r *RosterProvider) done(m *discordgo.MessageCreate) (string, error) {
 		return "", ErrorInternalError
 	}
 
-	statString := eq.PrintStats(eq.RaidWideClassCounts(toons))
+	a := model.Attendance{}
+	waitlist, err := a.GetWaitlist(r.pool, vs.(*rosterState).eventId)
+	if err != nil {
+		return "", ErrorInternalError
+	}
 
-	var (
-		mC, bC             int
-		boxString, mString []string
-	)
+	str := fmt.Sprintf("__Summary__:\n%s\n%s",
+		eq.PrintStats(eq.RaidWideClassCounts(toons)),
+		eq.PrintRoster(toons))
 
-	for _, t := range toons {
-		if t.CharacterType == model.TypeBox {
-			boxString = append(boxString, fmt.Sprintf("(%s)%s", eq.ClassAbbreviationsMap[t.Class], t.Name))
-			bC++
-		} else {
-			mString = append(mString, fmt.Sprintf("(%s)%s", eq.ClassAbbreviationsMap[t.Class], t.Name))
-			mC++
+	below, err := belowResists(r.pool, vs.(*rosterState).eventId, toons)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(below) > 0 {
+		str += fmt.Sprintf("\n **Below resists** - %d: %s", len(below), strings.Join(below, ", "))
+	}
+
+	missing, err := model.MissingFlags(r.pool, vs.(*rosterState).eventId, toons)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(missing) > 0 {
+		var lacking []string
+		for _, t := range toons {
+			if flags, ok := missing[t.Id]; ok {
+				lacking = append(lacking, fmt.Sprintf("%s (%s)", t.Name, strings.Join(flags, ", ")))
+			}
 		}
+		sort.Strings(lacking)
+
+		str += fmt.Sprintf("\n **Missing flags** - %d: %s", len(lacking), strings.Join(lacking, ", "))
 	}
 
-	sort.Strings(boxString)
-	sort.Strings(mString)
+	if len(waitlist) > 0 {
+		var charIds []int64
+		for _, w := range waitlist {
+			charIds = append(charIds, w.CharacterId)
+		}
+
+		waiting, err := c.GetWhereIn(r.pool, charIds)
+		if err != nil {
+			return "", ErrorInternalError
+		}
+
+		names := make(map[int64]string)
+		for _, t := range waiting {
+			names[t.Id] = fmt.Sprintf("(%s)%s", eq.ClassAbbreviationsMap[t.Class], t.Name)
+		}
+
+		var waitString []string
+		for _, w := range waitlist {
+			waitString = append(waitString, names[w.CharacterId])
+		}
 
-	str := fmt.Sprintf("__Summary__:\n%s\n**Mains** - %d: %s \n **Boxes** - %d: %s",
-		statString,
-		mC,
-		strings.Join(mString, ", "),
-		bC,
-		strings.Join(boxString, ", "))
+		str += fmt.Sprintf("\n **Waitlist** - %d: %s", len(waitString), strings.Join(waitString, ", "))
+	}
 
 	r.Reset(m)
 
 	return str, nil
 }
 
+// belowResists lists the attendees that have not recorded the resists the event requires
+func belowResists(db *pgxpool.Pool, eventId int64, toons []model.Character) ([]string, error) {
+	er := model.EventResist{}
+	need, err := er.GetForEvent(db, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(need) == 0 || len(toons) == 0 {
+		return nil, nil
+	}
+
+	var ids []int64
+	for _, t := range toons {
+		ids = append(ids, t.Id)
+	}
+
+	cr := model.CharacterResist{}
+	have, err := cr.GetForCharacters(db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	var below []string
+	for _, t := range toons {
+		if short := eq.ResistShortfall(have[t.Id], need); len(short) > 0 {
+			below = append(below, fmt.Sprintf("%s (%s)", t.Name, strings.Join(short, ", ")))
+		}
+	}
+	sort.Strings(below)
+
+	return below, nil
+}
+
 func (r *RosterProvider) Reset(m *discordgo.MessageCreate) {
 	delete(r.registry, m.Author.ID)
 	delete(r.eventReg, m.Author.ID)
@@ -140,9 +140,11 @@ func (r *SplitProvider) event(m *discordgo.MessageCreate) (string, error) {
 
 	vs := r.registry[m.Author.ID]
 
+	var event model.Event
 	for k, v := range r.eventReg[m.Author.ID] {
 		if k == i {
 			vs.(*splitState).eventId = v.Id
+			event = v
 			break
 		}
 	}
@@ -154,6 +156,10 @@ func (r *SplitProvider) event(m *discordgo.MessageCreate) (string, error) {
 		r.registry[m.Author.ID] = vs
 	}
 
+	if event.SplitCount > 1 {
+		return fmt.Sprintf("How many ways should I split this event? This event is usually split %d ways.", event.SplitCount), nil
+	}
+
 	return "How many ways should I split this event? e.g. 4", nil
 }
 
@@ -168,23 +174,66 @@ func (r *SplitProvider) split(m *discordgo.MessageCreate) (string, error) {
 		return "", errors.New("you cannot one split an event")
 	}
 
-	a := model.Attendance{}
+	eventId := r.registry[m.Author.ID].(*splitState).eventId
+
+	var event model.Event
+	for _, e := range r.eventReg[m.Author.ID] {
+		if e.Id == eventId {
+			event = e
+		}
+	}
 
-	attendees, err := a.GetAttendees(r.pool, r.registry[m.Author.ID].(*splitState).eventId)
+	a := model.Attendance{}
+	signedUp, err := a.GetAttendees(r.pool, eventId)
 	if err != nil {
 		return "", ErrorInternalError
 	}
 
+	// characters signed up before the events limits changed are left out
+	var attendees []model.Character
+	for _, c := range signedUp {
+		if event.Accepts(c) {
+			attendees = append(attendees, c)
+		}
+	}
+
 	if len(attendees) == 0 {
 		r.Reset(m)
 		return "No one is coming to this event.  Try agian when more people have registered.", nil
 	}
 
-	var splitString string
+	pa := model.PlayerAccount{}
+	owners, err := pa.GetOwnerKeys(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
 
 	splitter := eq.NewSplitter(attendees, false)
+	splitter.LinkOwners(owners)
 	splits, stats := splitter.Split(i)
 
+	es := model.EventSplit{}
+	if err = es.ReplaceForEvent(r.pool, eventId, splits); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	missing, err := model.MissingFlags(r.pool, eventId, attendees)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.Reset(m)
+
+	return formatSplits(splits, stats, missing), nil
+}
+
+// formatSplits writes out each raid and its groups, characters in missing are marked with the flags they lack
+func formatSplits(splits [][][]model.Character, stats []map[int64]int, missing map[int64][]string) string {
+	var splitString string
+
 	for raidI, split := range splits {
 		splitString += fmt.Sprintf("\n*** ===> Raid %d <===***\n", raidI+1)
 		splitString += fmt.Sprintf("%s\n", eq.PrintStats(stats[raidI]))
@@ -192,19 +241,20 @@ func (r *SplitProvider) split(m *discordgo.MessageCreate) (string, error) {
 			splitString += fmt.Sprintf("__Group %d__\n", g+1)
 			var items []string
 			for _, c := range group {
+				item := fmt.Sprintf("%s-%s", c.Name, eq.ClassAbbreviationsMap[c.Class])
 				if c.CharacterType == model.TypeBox {
-					items = append(items, fmt.Sprintf("%s-%s(box)", c.Name, eq.ClassAbbreviationsMap[c.Class]))
-				} else {
-					items = append(items, fmt.Sprintf("%s-%s", c.Name, eq.ClassAbbreviationsMap[c.Class]))
+					item += "(box)"
 				}
+				if flags, ok := missing[c.Id]; ok {
+					item += fmt.Sprintf("(missing %s)", strings.Join(flags, ", "))
+				}
+				items = append(items, item)
 			}
 			splitString += strings.Join(items, ", ") + "\n"
 		}
 	}
 
-	r.Reset(m)
-
-	return splitString, nil
+	return splitString
 }
 
 func (r *SplitProvider) Reset(m *discordgo.MessageCreate) {
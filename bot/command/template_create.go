@@ -0,0 +1,299 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	templateStateStart     = 0
+	templateStateName      = 1
+	templateStateDesc      = 2
+	templateStateLevel     = 3
+	templateStatePolicy    = 4
+	templateStateResists   = 5
+	templateStateSplits    = 6
+	templateStateDuration  = 7
+	templateStateRepeating = 8
+	templateStateDone      = 9
+	templateStateSaved     = 10
+)
+
+type CreateTemplateProvider struct {
+	pool     *pgxpool.Pool
+	registry StateRegistry
+	manifest *Manifest
+}
+
+type templateState struct {
+	userId      string
+	name        string
+	description string
+	minLevel    int64
+	types       []int64
+	resists     map[int64]int64
+	splitCount  int64
+	duration    int64
+	repeats     bool
+	state       int64
+	ttl         time.Time
+}
+
+func (r *templateState) IsComplete() bool {
+	return r.state == templateStateSaved
+}
+
+func (r *templateState) Step() int64 {
+	return r.state
+}
+
+func (r *templateState) TTL() time.Time {
+	return r.ttl
+}
+
+func (r *templateState) toModel() *model.EventTemplate {
+	return &model.EventTemplate{
+		Name:         r.name,
+		Description:  r.description,
+		MinLevel:     r.minLevel,
+		SplitCount:   r.splitCount,
+		IsRepeatable: r.repeats,
+		Duration:     r.duration,
+		AllowedTypes: r.types,
+		CreatedBy:    r.userId,
+	}
+}
+
+func NewCreateTemplateProvider(db *pgxpool.Pool) *CreateTemplateProvider {
+	provider := &CreateTemplateProvider{
+		pool:     db,
+		registry: make(StateRegistry),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.name,
+		provider.description,
+		provider.level,
+		provider.policy,
+		provider.resists,
+		provider.splits,
+		provider.duration,
+		provider.repeating,
+		provider.done,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *CreateTemplateProvider) Name() string {
+	return TemplateCreate
+}
+
+func (r *CreateTemplateProvider) Description() string {
+	return "saves an event template that can be used when creating events, not available to all users"
+}
+
+func (r *CreateTemplateProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+	})
+}
+
+func (r *CreateTemplateProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *CreateTemplateProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to create event templates.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *CreateTemplateProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		r.registry[m.Author.ID] = &templateState{
+			state:  templateStateName,
+			ttl:    time.Now().Add(commandCacheWindow),
+			userId: m.Author.ID,
+		}
+		return fmt.Sprintf("Hello %s, what is the name of this template? Events created from it will use this as their title.", m.Author.Username), nil
+	}
+	return "", nil
+}
+
+func (r *CreateTemplateProvider) name(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*templateState)
+	v.name = m.Content
+	v.state = templateStateDesc
+
+	return "Enter a description", nil
+}
+
+func (r *CreateTemplateProvider) description(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*templateState)
+	v.description = m.Content
+	v.state = templateStateLevel
+
+	return "What is the minimum level for this event? Respond with 0 for no minimum.", nil
+}
+
+func (r *CreateTemplateProvider) level(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.ParseInt(m.Content, 10, 64)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	if i > eq.MaxLevel() || i < 0 {
+		return "", fmt.Errorf("the minimum level must be between 0 and %d", eq.MaxLevel())
+	}
+
+	v := r.registry[m.Author.ID].(*templateState)
+	v.minLevel = i
+	v.state = templateStatePolicy
+
+	return fmt.Sprintf("Who may attend this event? Respond with one of %s", strings.Join(model.PolicyNames(), ", ")), nil
+}
+
+func (r *CreateTemplateProvider) policy(m *discordgo.MessageCreate) (string, error) {
+	types, ok := model.PolicyByName(m.Content)
+	if !ok {
+		return "", fmt.Errorf("%s is not a policy, choose one of %s", m.Content, strings.Join(model.PolicyNames(), ", "))
+	}
+
+	v := r.registry[m.Author.ID].(*templateState)
+	v.types = types
+	v.state = templateStateResists
+
+	return "What resists does this event need? e.g. FR:150,CR:100 or respond with none.", nil
+}
+
+func (r *CreateTemplateProvider) resists(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*templateState)
+
+	v.resists = make(map[int64]int64)
+	if !strings.EqualFold(strings.TrimSpace(m.Content), "none") {
+		resists, err := eq.ParseResists(m.Content)
+		if err != nil {
+			return "", err
+		}
+		v.resists = resists
+	}
+	v.state = templateStateSplits
+
+	return "How many ways is this event usually split? Respond with 0 if it is not split.", nil
+}
+
+func (r *CreateTemplateProvider) splits(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.ParseInt(m.Content, 10, 64)
+	if err != nil || i < 0 {
+		return "", ErrorInvalidInput
+	}
+
+	v := r.registry[m.Author.ID].(*templateState)
+	v.splitCount = i
+	v.state = templateStateDuration
+
+	return eventDurationPrompt, nil
+}
+
+func (r *CreateTemplateProvider) duration(m *discordgo.MessageCreate) (string, error) {
+	d, err := parseEventDuration(m.Content)
+	if err != nil {
+		return "", err
+	}
+
+	v := r.registry[m.Author.ID].(*templateState)
+	v.duration = d
+	v.state = templateStateRepeating
+
+	return `Does the event repeat weekly?. (1 or 2)
+1. Yes
+2. No`, nil
+}
+
+func (r *CreateTemplateProvider) repeating(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*templateState)
+
+	switch m.Content {
+	case "1":
+		v.repeats = true
+	case "2":
+		v.repeats = false
+	default:
+		return "", ErrorInvalidInput
+	}
+
+	v.state = templateStateDone
+
+	msg := `Does this all look correct?. (1 or 2)
+Name: %s
+Description: %s
+Minimum level: %d
+Who may attend: %s
+Resists: %s
+Splits: %d
+Duration: %s
+Repeats weekly: %t
+
+1. Yes
+2. No`
+
+	return fmt.Sprintf(msg,
+		v.name,
+		v.description,
+		v.minLevel,
+		model.PolicyName(v.types),
+		eq.FormatResists(v.resists),
+		v.splitCount,
+		formatEventDuration(v.duration),
+		v.repeats), nil
+}
+
+func (r *CreateTemplateProvider) done(m *discordgo.MessageCreate) (string, error) {
+	if m.Content == "1" {
+		dat := r.registry[m.Author.ID].(*templateState)
+		t := dat.toModel()
+		err := t.Save(r.pool)
+		if err != nil {
+			log.Printf(err.Error())
+			return "", fmt.Errorf("could not save the template, is there already one called %s?", dat.name)
+		}
+
+		tr := model.TemplateResist{}
+		if err = tr.ReplaceForTemplate(r.pool, t.Id, dat.resists); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+		r.Reset(m)
+		return "The template has been saved", nil
+	} else if m.Content == "2" {
+		r.Reset(m)
+		return "Resetting the template", nil
+	} else {
+		return "", ErrorInvalidInput
+	}
+}
+
+func (r *CreateTemplateProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+}
@@ -0,0 +1,155 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	deleteTemplateStateStart  = 0
+	deleteTemplateStateChoose = 1
+	deleteTemplateStateDone   = 2
+)
+
+type deleteTemplateState struct {
+	state  int64
+	userId string
+	ttl    time.Time
+}
+
+func (r *deleteTemplateState) IsComplete() bool {
+	return r.state == deleteTemplateStateDone
+}
+
+func (r *deleteTemplateState) Step() int64 {
+	return r.state
+}
+
+func (r *deleteTemplateState) TTL() time.Time {
+	return r.ttl
+}
+
+type DeleteTemplateProvider struct {
+	pool        *pgxpool.Pool
+	registry    StateRegistry
+	templateReg map[string]map[int]model.EventTemplate
+	manifest    *Manifest
+}
+
+func NewDeleteTemplateProvider(db *pgxpool.Pool) *DeleteTemplateProvider {
+	provider := &DeleteTemplateProvider{
+		pool:        db,
+		registry:    make(StateRegistry),
+		templateReg: make(map[string]map[int]model.EventTemplate),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *DeleteTemplateProvider) Name() string {
+	return TemplateDelete
+}
+
+func (r *DeleteTemplateProvider) Description() string {
+	return "deletes a saved event template, not available to all users"
+}
+
+func (r *DeleteTemplateProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.templateReg, k)
+	})
+}
+
+func (r *DeleteTemplateProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *DeleteTemplateProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to delete event templates.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *DeleteTemplateProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; !ok {
+		t := model.EventTemplate{}
+		templates, err := t.GetAll(r.pool)
+		if err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
+		}
+
+		if len(templates) == 0 {
+			return "", errors.New("there are no templates to delete")
+		}
+
+		r.registry[m.Author.ID] = &deleteTemplateState{
+			state:  deleteTemplateStateChoose,
+			userId: m.Author.ID,
+			ttl:    time.Now().Add(commandCacheWindow),
+		}
+
+		r.templateReg[m.Author.ID] = make(map[int]model.EventTemplate)
+
+		var templateString []string
+		for i, t := range templates {
+			r.templateReg[m.Author.ID][i] = t
+			templateString = append(templateString, fmt.Sprintf("%d. %s", i, t.Name))
+		}
+
+		return fmt.Sprintf("Which template would you like to delete?\n%s", strings.Join(templateString, "\n")), nil
+	}
+
+	return "", nil
+}
+
+func (r *DeleteTemplateProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	t, ok := r.templateReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid template selection")
+	}
+
+	if err = t.Delete(r.pool); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.Reset(m)
+
+	return fmt.Sprintf("Deleted the %s template.", t.Name), nil
+}
+
+func (r *DeleteTemplateProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.templateReg, m.Author.ID)
+}
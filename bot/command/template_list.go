@@ -0,0 +1,81 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"fmt"
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"log"
+	"strings"
+)
+
+type ListTemplatesProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewListTemplatesProvider(db *pgxpool.Pool) *ListTemplatesProvider {
+	provider := &ListTemplatesProvider{pool: db}
+
+	steps := []Step{
+		provider.list,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *ListTemplatesProvider) Name() string {
+	return TemplateList
+}
+
+func (r *ListTemplatesProvider) Description() string {
+	return "lists all saved event templates"
+}
+
+func (r *ListTemplatesProvider) Cleanup() {
+}
+
+func (r *ListTemplatesProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *ListTemplatesProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *ListTemplatesProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *ListTemplatesProvider) list(m *discordgo.MessageCreate) (string, error) {
+	t := model.EventTemplate{}
+	templates, err := t.GetAll(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(templates) == 0 {
+		return "No templates found.", nil
+	}
+
+	var templateList []string
+	for i, t := range templates {
+		templateList = append(templateList, formatTemplate(i+1, t))
+	}
+
+	return fmt.Sprintf("All saved templates are listed below.\n%s", strings.Join(templateList, "\n")), nil
+}
+
+func formatTemplate(i int, t model.EventTemplate) string {
+	return fmt.Sprintf("**%d. %s**: %s (level %d+, %s, %d splits, %s, repeats weekly: %t)",
+		i,
+		t.Name,
+		t.Description,
+		t.MinLevel,
+		model.PolicyName(t.AllowedTypes),
+		t.SplitCount,
+		formatEventDuration(t.Duration),
+		t.IsRepeatable)
+}
@@ -0,0 +1,124 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type TradeskillProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewTradeskillProvider(db *pgxpool.Pool) *TradeskillProvider {
+	provider := &TradeskillProvider{pool: db}
+
+	steps := []Step{
+		provider.tradeskill,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *TradeskillProvider) Name() string {
+	return Tradeskill
+}
+
+func (r *TradeskillProvider) Description() string {
+	return "records the tradeskills of one of your characters e.g. !tradeskill Clericbot smithing:250 jc:180, on its own lists them"
+}
+
+func (r *TradeskillProvider) Cleanup() {
+}
+
+func (r *TradeskillProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *TradeskillProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *TradeskillProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *TradeskillProvider) tradeskill(m *discordgo.MessageCreate) (string, error) {
+	c := model.Character{}
+	toons, err := c.GetByOwner(r.pool, m.Author.ID)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(toons) == 0 {
+		return "", fmt.Errorf("you have no characters registered, please type **%s** to add one", Register)
+	}
+
+	fields := strings.SplitN(strings.TrimSpace(strings.TrimPrefix(m.Content, Tradeskill)), " ", 2)
+	if fields[0] == "" {
+		return r.list(toons)
+	}
+
+	var toon *model.Character
+	for i := range toons {
+		if strings.EqualFold(toons[i].Name, fields[0]) {
+			toon = &toons[i]
+		}
+	}
+
+	if toon == nil {
+		return "", fmt.Errorf("%s is not one of your characters", fields[0])
+	}
+
+	if len(fields) < 2 {
+		return "", fmt.Errorf("please include the skills to record e.g. %s %s smithing:250 tailoring:180", Tradeskill, toon.Name)
+	}
+
+	skills, err := eq.ParseTradeskills(fields[1])
+	if err != nil {
+		return "", err
+	}
+
+	ct := model.CharacterTradeskill{}
+	if err = ct.SetForCharacter(r.pool, toon.Id, skills); err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	recorded, err := ct.GetForCharacters(r.pool, []int64{toon.Id})
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	return fmt.Sprintf("Recorded the tradeskills of %s: %s.", toon.Name, eq.FormatTradeskills(recorded[toon.Id])), nil
+}
+
+func (r *TradeskillProvider) list(toons []model.Character) (string, error) {
+	var ids []int64
+	for _, t := range toons {
+		ids = append(ids, t.Id)
+	}
+
+	ct := model.CharacterTradeskill{}
+	skills, err := ct.GetForCharacters(r.pool, ids)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	var lines []string
+	for _, t := range toons {
+		lines = append(lines, fmt.Sprintf("%s - %s", t.Name, eq.FormatTradeskills(skills[t.Id])))
+	}
+
+	return fmt.Sprintf("__Recorded tradeskills__\n%s", strings.Join(lines, "\n")), nil
+}
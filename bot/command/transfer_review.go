@@ -0,0 +1,237 @@
+package command
+
+import (
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	reviewStateStart    = 0
+	reviewStateTransfer = 1
+	reviewStateDecision = 2
+	reviewStateDone     = 3
+)
+
+type reviewState struct {
+	transfer  model.CharacterTransfer
+	character model.Character
+	state     int64
+	userId    string
+	ttl       time.Time
+}
+
+func (r *reviewState) IsComplete() bool {
+	return r.state == reviewStateDone
+}
+
+func (r *reviewState) Step() int64 {
+	return r.state
+}
+
+func (r *reviewState) TTL() time.Time {
+	return r.ttl
+}
+
+type TransferReviewProvider struct {
+	pool        *pgxpool.Pool
+	registry    StateRegistry
+	transferReg map[string]map[int]model.CharacterTransfer
+	charReg     map[string]map[int64]model.Character
+	manifest    *Manifest
+}
+
+func NewTransferReviewProvider(db *pgxpool.Pool) *TransferReviewProvider {
+	provider := &TransferReviewProvider{
+		pool:        db,
+		registry:    make(StateRegistry),
+		transferReg: make(map[string]map[int]model.CharacterTransfer),
+		charReg:     make(map[string]map[int64]model.Character),
+	}
+
+	steps := []Step{
+		provider.start,
+		provider.choose,
+		provider.decide,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *TransferReviewProvider) Name() string {
+	return TransferReview
+}
+
+func (r *TransferReviewProvider) Description() string {
+	return "approves or rejects character transfers, not available to all users"
+}
+
+func (r *TransferReviewProvider) Cleanup() {
+	cleanupCache(r.registry, func(k string) {
+		delete(r.registry, k)
+		delete(r.transferReg, k)
+		delete(r.charReg, k)
+	})
+}
+
+func (r *TransferReviewProvider) WorkflowForUser(userId string) State {
+	if v, ok := r.registry[userId]; ok {
+		return v
+	} else {
+		return nil
+	}
+}
+
+func (r *TransferReviewProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	if !isAllowed(m) {
+		err := sendMessage(s, m.ChannelID, "Only authorized users are allowed to review character transfers.")
+		if err != nil {
+			log.Print(err.Error())
+		}
+		return
+	}
+	genericStepwiseHandler(s, m, r.manifest, r.registry)
+}
+
+func (r *TransferReviewProvider) start(m *discordgo.MessageCreate) (string, error) {
+	if _, ok := r.registry[m.Author.ID]; ok {
+		return "", nil
+	}
+
+	ct := model.CharacterTransfer{}
+	transfers, err := ct.GetPending(r.pool)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if len(transfers) == 0 {
+		return "There are no transfers waiting for review.", nil
+	}
+
+	var ids []int64
+	for _, t := range transfers {
+		ids = append(ids, t.CharacterId)
+	}
+
+	c := model.Character{}
+	toons, err := c.GetWhereIn(r.pool, ids)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	r.charReg[m.Author.ID] = make(map[int64]model.Character)
+	for _, t := range toons {
+		r.charReg[m.Author.ID][t.Id] = t
+	}
+
+	r.registry[m.Author.ID] = &reviewState{
+		state:  reviewStateTransfer,
+		userId: m.Author.ID,
+		ttl:    time.Now().Add(commandCacheWindow),
+	}
+
+	r.transferReg[m.Author.ID] = make(map[int]model.CharacterTransfer)
+
+	var transferString []string
+	for i, t := range transfers {
+		r.transferReg[m.Author.ID][i] = t
+		transferString = append(transferString, fmt.Sprintf("%d. %s from %s to <@%s>", i, r.charReg[m.Author.ID][t.CharacterId].Name, transferSource(t), t.ToUser))
+	}
+
+	return fmt.Sprintf("Which transfer would you like to review?\n%s", strings.Join(transferString, "\n")), nil
+}
+
+func (r *TransferReviewProvider) choose(m *discordgo.MessageCreate) (string, error) {
+	i, err := strconv.Atoi(m.Content)
+	if err != nil {
+		return "", ErrorInvalidInput
+	}
+
+	t, ok := r.transferReg[m.Author.ID][i]
+	if !ok {
+		return "", errors.New("invalid transfer selection")
+	}
+
+	v := r.registry[m.Author.ID].(*reviewState)
+	v.transfer = t
+	v.character = r.charReg[m.Author.ID][t.CharacterId]
+	v.state = reviewStateDecision
+
+	return fmt.Sprintf("Move %s from %s to <@%s>?\n1. Approve\n2. Reject", v.character.Name, transferSource(t), t.ToUser), nil
+}
+
+func (r *TransferReviewProvider) decide(m *discordgo.MessageCreate) (string, error) {
+	v := r.registry[m.Author.ID].(*reviewState)
+	t := v.transfer
+
+	var msg string
+	switch m.Content {
+	case "1":
+		// the new owner may already have a main or as many boxes as they are allowed
+		charType := v.character.CharacterType
+		if err := checkTypeLimit(r.pool, t.ToUser, v.character.Id, charType); err != nil {
+			if err == ErrorInternalError {
+				return "", err
+			}
+			charType = model.TypeAlt
+		}
+
+		if err := t.Approve(r.pool, m.Author.ID, charType); err != nil {
+			log.Println(err.Error())
+			r.Reset(m)
+			if errors.Is(err, model.ErrTransferStale) {
+				return "", fmt.Errorf("%s no longer belongs to %s, the transfer has been rejected", v.character.Name, transferSource(t))
+			}
+			return "", fmt.Errorf("could not transfer %s: %s", v.character.Name, err.Error())
+		}
+		msg = fmt.Sprintf("%s has been moved from %s to <@%s> as a %s.", v.character.Name, transferSource(t), t.ToUser, strings.ToLower(model.CharTypeMap[charType]))
+	case "2":
+		if err := t.Reject(r.pool, m.Author.ID); err != nil {
+			log.Println(err.Error())
+			r.Reset(m)
+			return "", fmt.Errorf("could not reject the transfer of %s: %s", v.character.Name, err.Error())
+		}
+		msg = fmt.Sprintf("The transfer of %s from %s to <@%s> was rejected.", v.character.Name, transferSource(t), t.ToUser)
+	default:
+		return "", ErrorInvalidInput
+	}
+
+	for _, id := range []string{t.FromUser, t.ToUser} {
+		if id == "" {
+			continue
+		}
+		n := model.Notification{UserId: id, Message: msg}
+		if err := n.Save(r.pool); err != nil {
+			log.Println(err.Error())
+		}
+	}
+
+	r.Reset(m)
+
+	return msg, nil
+}
+
+func (r *TransferReviewProvider) Reset(m *discordgo.MessageCreate) {
+	delete(r.registry, m.Author.ID)
+	delete(r.transferReg, m.Author.ID)
+	delete(r.charReg, m.Author.ID)
+}
+
+// transferSource names who a character is moving from, claims of imported characters come from the guild roster
+func transferSource(t model.CharacterTransfer) string {
+	if t.FromUser == "" {
+		return "the guild roster"
+	}
+	return fmt.Sprintf("<@%s>", t.FromUser)
+}
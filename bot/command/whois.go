@@ -0,0 +1,107 @@
+package command
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+type WhoisProvider struct {
+	pool     *pgxpool.Pool
+	manifest *Manifest
+}
+
+func NewWhoisProvider(db *pgxpool.Pool) *WhoisProvider {
+	provider := &WhoisProvider{pool: db}
+
+	steps := []Step{
+		provider.whois,
+	}
+
+	provider.manifest = &Manifest{Steps: steps}
+
+	return provider
+}
+
+func (r *WhoisProvider) Name() string {
+	return Whois
+}
+
+func (r *WhoisProvider) Description() string {
+	return "shows who owns a character and everything else they play e.g. !whois Clericbot"
+}
+
+func (r *WhoisProvider) Cleanup() {
+}
+
+func (r *WhoisProvider) Reset(m *discordgo.MessageCreate) {
+}
+
+func (r *WhoisProvider) WorkflowForUser(userId string) State {
+	return nil
+}
+
+func (r *WhoisProvider) Handle(s *discordgo.Session, m *discordgo.MessageCreate) {
+	genericSimpleHandler(s, m, r.manifest)
+}
+
+func (r *WhoisProvider) whois(m *discordgo.MessageCreate) (string, error) {
+	name := strings.TrimSpace(strings.TrimPrefix(m.Content, Whois))
+	if name == "" {
+		return "", errors.New("please include the character name e.g. !whois Clericbot")
+	}
+
+	c := model.Character{}
+	toon, ok, err := c.GetByName(r.pool, name)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	if !ok {
+		return "", fmt.Errorf("there is no character called %s", name)
+	}
+
+	if !toon.IsClaimed() {
+		return fmt.Sprintf("%s - %d %s has not been claimed by anyone yet.", toon.Name, toon.Level, eq.RaceClassString(toon.Race, toon.Class)), nil
+	}
+
+	toons, err := c.GetByOwner(r.pool, toon.CreatedBy)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	pa := model.PlayerAccount{}
+	accounts, err := pa.GetLinked(r.pool, toon.CreatedBy)
+	if err != nil {
+		log.Println(err.Error())
+		return "", ErrorInternalError
+	}
+
+	retired := ""
+	if toon.Retired {
+		retired = " (retired)"
+	}
+
+	byType := make(map[int64][]string)
+	for _, t := range toons {
+		byType[t.CharacterType] = append(byType[t.CharacterType], fmt.Sprintf("%s - %d %s (%d AA)", t.Name, t.Level, eq.RaceClassString(t.Race, t.Class), t.AA))
+	}
+
+	str := fmt.Sprintf("**%s**%s belongs to %s", toon.Name, retired, formatMentions(accounts))
+	for _, charType := range []int64{model.TypeMain, model.TypeBox, model.TypeAlt} {
+		if len(byType[charType]) == 0 {
+			continue
+		}
+		str += fmt.Sprintf("\n__%ss__\n%s", model.CharTypeMap[charType], strings.Join(byType[charType], "\n"))
+	}
+
+	return str, nil
+}
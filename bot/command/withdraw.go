@@ -164,6 +164,7 @@ func (p *WithdrawProvider) next(m *discordgo.MessageCreate) (string, error) {
 		return "", ErrorInternalError
 	}
 
+	var withdrew bool
 	for i := range att {
 		if !att[i].Withdrawn {
 			att[i].Withdrawn = true
@@ -171,6 +172,14 @@ func (p *WithdrawProvider) next(m *discordgo.MessageCreate) (string, error) {
 			if err != nil {
 				return "", ErrorInternalError
 			}
+			withdrew = true
+		}
+	}
+
+	if withdrew {
+		if err = p.promote(nextEvent); err != nil {
+			log.Println(err.Error())
+			return "", ErrorInternalError
 		}
 	}
 	p.Reset(m)
@@ -178,6 +187,25 @@ func (p *WithdrawProvider) next(m *discordgo.MessageCreate) (string, error) {
 	return fmt.Sprintf("%d attendees have been marked as absent from %s on %v", len(att), nextEvent.Title, nextEvent.EventTime), nil
 }
 
+// promote fills the spots freed by a withdrawal from the events waitlist straight away
+func (p *WithdrawProvider) promote(event model.Event) error {
+	c := model.EventClassCap{}
+	caps, err := c.GetForEvent(p.db, event.Id)
+	if err != nil {
+		return err
+	}
+
+	a := model.Attendance{}
+	attending, err := a.GetAttendees(p.db, event.Id)
+	if err != nil {
+		return err
+	}
+
+	_, err = a.PromoteWaitlist(p.db, event, caps, attending)
+
+	return err
+}
+
 func (p *WithdrawProvider) handleEvent(m *discordgo.MessageCreate) (string, error) {
 	return "", nil
 }
@@ -23,11 +23,50 @@ func NewCommandController(db *pgxpool.Pool) *CommandController {
 	providers := []command.Provider{
 		command.NewMyCharactersProvider(db),
 		command.NewRegistrationProvider(db),
+		command.NewCharacterEditProvider(db),
+		command.NewCharacterRetireProvider(db),
+		command.NewCharacterDisputeProvider(db),
+		command.NewCharacterTransferProvider(db),
+		command.NewTransferReviewProvider(db),
+		command.NewAccountLinkProvider(db),
+		command.NewAccountUnlinkProvider(db),
 		command.NewListEventsProvider(db),
 		command.NewCreateEventProvider(db),
 		command.NewSplitProvider(db),
 		command.NewRosterProvider(db),
+		command.NewWhoisProvider(db),
+		command.NewFindProvider(db),
+		command.NewResistsProvider(db),
+		command.NewFlagCreateProvider(db),
+		command.NewFlagListProvider(db),
+		command.NewFlagReportProvider(db),
+		command.NewFlagVerifyProvider(db),
+		command.NewEpicProvider(db),
+		command.NewEpicReportProvider(db),
+		command.NewEpicStepProvider(db),
+		command.NewTradeskillProvider(db),
+		command.NewCraftersProvider(db),
+		command.NewHasProvider(db),
+		command.NewProgressProvider(db),
+		command.NewProgressReportProvider(db),
+		command.NewAttendanceProvider(db),
 		command.NewWithdrawProvider(db),
+		command.NewEditEventProvider(db),
+		command.NewEventHistoryProvider(db),
+		command.NewEventLimitsProvider(db),
+		command.NewStartEventProvider(db),
+		command.NewEndEventProvider(db),
+		command.NewCancelEventProvider(db),
+		command.NewCheckInProvider(db),
+		command.NewCreateTemplateProvider(db),
+		command.NewListTemplatesProvider(db),
+		command.NewDeleteTemplateProvider(db),
+		command.NewImportGuildProvider(db),
+		command.NewImportRaidProvider(db),
+		command.NewImportLogProvider(db),
+		command.NewImportInventoryProvider(db),
+		command.NewClaimProvider(db),
+		command.NewGuildSettingsProvider(db),
 	}
 
 	for _, p := range providers {
@@ -51,14 +90,15 @@ func (r *CommandController) MessageCreatedHandler(s *discordgo.Session, m *disco
 	cmd := regMatch.FindString(m.Content)
 
 	// only switch on valid commands
-	switch cmd {
-	case command.Register, command.MyCharacters, command.ListEvents, command.CreateEvent, command.Split, command.Roster, command.Withdraw:
+	p, isCommand := r.providers[cmd]
+	switch {
+	case isCommand:
 		log.Printf("processing command %s for user %s", cmd, m.Author.ID)
 		for _, r := range r.providers {
 			r.Reset(m)
 		}
-		r.providers[cmd].Handle(s, m)
-	case command.Help:
+		p.Handle(s, m)
+	case cmd == command.Help:
 		r.help(s, m)
 	default:
 		for _, p := range r.providers {
@@ -109,9 +149,11 @@ func (r *CommandController) help(s *discordgo.Session, m *discordgo.MessageCreat
 		r.helpStr = cmdListString
 	}
 
-	_, err := s.ChannelMessageSend(m.ChannelID, fmt.Sprintf(helpMessage, r.helpStr))
-	if err != nil {
-		log.Print(err.Error())
-		return
+	// the command list is longer than discord allows in one message
+	for _, msg := range command.ChunkMsg([]rune(fmt.Sprintf(helpMessage, r.helpStr)), 1000) {
+		if _, err := s.ChannelMessageSend(m.ChannelID, msg); err != nil {
+			log.Print(err.Error())
+			return
+		}
 	}
 }
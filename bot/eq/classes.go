@@ -7,7 +7,8 @@ import (
 	"strings"
 )
 
-const MaxLevel = 60
+// aaPerLevel is how many AA points are considered worth one level when scoring characters
+const aaPerLevel = 15
 
 const (
 	classWarrior      = 1
@@ -24,6 +25,8 @@ const (
 	classDruid        = 12
 	classCleric       = 13
 	classBard         = 14
+	classBeastlord    = 15
+	classBerserker    = 16
 
 	classTypeTank   = "tank"
 	classTypeMelee  = "melee"
@@ -32,34 +35,38 @@ const (
 	classTypeBard   = "bard"
 )
 
+// classRangeMap ranks the classes of a raid role, lower ranks are picked first. The role tables cover every
+// expansion and are narrowed to the active ruleset with roleRanks before use
 type classRangeMap map[int64]int
 
-var Tanks = classRangeMap{
+var tankRanks = classRangeMap{
 	classWarrior:      1,
 	classPaladin:      2,
 	classShadowknight: 2,
 }
 
-var MeleeDps = classRangeMap{
-	classMonk:   1,
-	classRogue:  2,
-	classRanger: 3,
+var meleeRanks = classRangeMap{
+	classMonk:      1,
+	classRogue:     2,
+	classBerserker: 2,
+	classBeastlord: 3,
+	classRanger:    3,
 }
 
-var Healers = classRangeMap{
+var healerRanks = classRangeMap{
 	classCleric: 1,
 	classDruid:  2,
 	classShaman: 3,
 }
 
-var CasterDps = classRangeMap{
+var casterRanks = classRangeMap{
 	classNecromancer: 1,
 	classEnchanter:   1,
 	classMagician:    2,
 	classWizard:      2,
 }
 
-var Bards = classRangeMap{
+var bardRanks = classRangeMap{
 	classBard: 1,
 }
 
@@ -78,6 +85,8 @@ var ClassChoiceMap = map[int64]string{
 	classDruid:        "Druid",
 	classCleric:       "Cleric",
 	classBard:         "Bard",
+	classBeastlord:    "Beastlord",
+	classBerserker:    "Berserker",
 }
 
 var ClassAbbreviationsMap = map[int64]string{
@@ -95,12 +104,67 @@ var ClassAbbreviationsMap = map[int64]string{
 	classDruid:        "DRU",
 	classCleric:       "CLR",
 	classBard:         "BRD",
+	classBeastlord:    "BST",
+	classBerserker:    "BER",
 }
 
+// EpicWeaponMap names the epic weapon each class works toward
+var EpicWeaponMap = map[int64]string{
+	classWarrior:      "Jagged Blade of War",
+	classMonk:         "Celestial Fists",
+	classRogue:        "Ragebringer",
+	classPaladin:      "Fiery Defender",
+	classShadowknight: "Innoruuk's Curse",
+	classRanger:       "Earthcaller and Swiftwind",
+	classEnchanter:    "Staff of the Serpent",
+	classWizard:       "Staff of the Four",
+	classMagician:     "Orb of Mastery",
+	classNecromancer:  "Scythe of the Shadowed Soul",
+	classShaman:       "Spear of Fate",
+	classDruid:        "Nature Walker's Scimitar",
+	classCleric:       "Water Sprinkler of Nem Ankh",
+	classBard:         "Singing Short Sword",
+	classBeastlord:    "Claws of the Savage Spirit",
+	classBerserker:    "Kerasian Axe of Ire",
+}
+
+// EpicSteps lists the steps of a class epic in the order they are completed, milestones are the guilds catalog
+// for the class and the finished weapon is always the last step
+func EpicSteps(class int64, milestones []string) []string {
+	weapon, ok := EpicWeaponMap[class]
+	if !ok {
+		return nil
+	}
+	return append(append([]string{}, milestones...), weapon)
+}
+
+// EpicProgress describes how far along its class epic a character is, step is the number of milestones completed
+// and only a finished epic counts the weapon
+func EpicProgress(class int64, milestones []string, step int64, finished bool) string {
+	steps := EpicSteps(class, milestones)
+	if len(steps) == 0 {
+		return "not started, 0/0"
+	}
+
+	if finished {
+		return fmt.Sprintf("%s, %d/%d", steps[len(steps)-1], len(steps), len(steps))
+	}
+
+	if step > int64(len(milestones)) {
+		step = int64(len(milestones))
+	}
+
+	if step <= 0 {
+		return fmt.Sprintf("not started, 0/%d", len(steps))
+	}
+
+	return fmt.Sprintf("%s, %d/%d", steps[step-1], step, len(steps))
+}
+
+// ClassChoiceString lists the classes of the active ruleset
 var ClassChoiceString = func() string {
 	str := ""
-	var i int64
-	for i = 1; i <= int64(len(ClassChoiceMap)); i++ {
+	for _, i := range ActiveRuleset().Classes {
 		str += fmt.Sprintf("%d. %s\n", i, ClassChoiceMap[i])
 	}
 	return str
@@ -123,17 +187,25 @@ func raidWideClassGroups(characters []model.Character) map[int64][]model.Charact
 	return classGroups
 }
 
+// PrintStats lists how many of each class of the active ruleset there are, classes outside the ruleset are only listed when present
 func PrintStats(stats map[int64]int) string {
 	var (
 		ret   []string
 		total int
 	)
-	for class, count := range stats {
-		ret = append(ret, fmt.Sprintf("%s: %d", ClassChoiceMap[class], count))
-		total += count
+
+	rs := ActiveRuleset()
+	for _, class := range rs.Classes {
+		ret = append(ret, fmt.Sprintf("%s: %d", ClassChoiceMap[class], stats[class]))
+		total += stats[class]
 	}
 
-	sort.Strings(ret)
+	for class, count := range stats {
+		if !rs.HasClass(class) {
+			ret = append(ret, fmt.Sprintf("%s: %d", ClassChoiceMap[class], count))
+			total += count
+		}
+	}
 
 	return fmt.Sprintf("\nBreakdown: %d members - %s\n", total, strings.Join(ret, ", "))
 }
@@ -150,14 +222,19 @@ func RaidWideClassCounts(characters []model.Character) map[int64]int {
 	return classGroups
 }
 
+// PowerScore rates a character by combining its level and AA
+func PowerScore(c model.Character) int64 {
+	return c.Level*aaPerLevel + c.AA
+}
+
 func sortToons(group []model.Character) func(i, j int) bool {
 	return func(i, j int) bool {
-		if group[i].Level != group[j].Level {
-			return group[i].Level > group[j].Level
+		if PowerScore(group[i]) != PowerScore(group[j]) {
+			return PowerScore(group[i]) > PowerScore(group[j])
 		}
 
-		if group[i].AA != group[j].AA {
-			return group[i].AA > group[j].AA
+		if group[i].Level != group[j].Level {
+			return group[i].Level > group[j].Level
 		}
 
 		return group[i].CharacterType > group[j].CharacterType
@@ -167,8 +244,17 @@ func sortToons(group []model.Character) func(i, j int) bool {
 func selectionClassGroups(raidList []model.Character) map[string][]model.Character {
 	classes := make(map[string][]model.Character)
 
+	rs := ActiveRuleset()
+	var (
+		tanks   = rs.roleRanks(tankRanks)
+		melee   = rs.roleRanks(meleeRanks)
+		healers = rs.roleRanks(healerRanks)
+		casters = rs.roleRanks(casterRanks)
+		bards   = rs.roleRanks(bardRanks)
+	)
+
 	for _, k := range raidList {
-		if _, ok := Tanks[k.Class]; ok {
+		if _, ok := tanks[k.Class]; ok {
 			if _, ok := classes[classTypeTank]; ok {
 				classes[classTypeTank] = append(classes[classTypeTank], k)
 			} else {
@@ -177,7 +263,7 @@ func selectionClassGroups(raidList []model.Character) map[string][]model.Charact
 			continue
 		}
 
-		if _, ok := MeleeDps[k.Class]; ok {
+		if _, ok := melee[k.Class]; ok {
 			if _, ok := classes[classTypeMelee]; ok {
 				classes[classTypeMelee] = append(classes[classTypeMelee], k)
 			} else {
@@ -186,7 +272,7 @@ func selectionClassGroups(raidList []model.Character) map[string][]model.Charact
 			continue
 		}
 
-		if _, ok := Healers[k.Class]; ok {
+		if _, ok := healers[k.Class]; ok {
 			if _, ok := classes[classTypeHealer]; ok {
 				classes[classTypeHealer] = append(classes[classTypeHealer], k)
 			} else {
@@ -195,7 +281,7 @@ func selectionClassGroups(raidList []model.Character) map[string][]model.Charact
 			continue
 		}
 
-		if _, ok := CasterDps[k.Class]; ok {
+		if _, ok := casters[k.Class]; ok {
 			if _, ok := classes[classTypeCaster]; ok {
 				classes[classTypeCaster] = append(classes[classTypeCaster], k)
 			} else {
@@ -204,7 +290,7 @@ func selectionClassGroups(raidList []model.Character) map[string][]model.Charact
 			continue
 		}
 
-		if _, ok := Bards[k.Class]; ok {
+		if _, ok := bards[k.Class]; ok {
 			if _, ok := classes[classTypeBard]; ok {
 				classes[classTypeBard] = append(classes[classTypeBard], k)
 			} else {
@@ -223,19 +309,19 @@ func selectionClassGroups(raidList []model.Character) map[string][]model.Charact
 		switch class {
 		case classTypeTank:
 			sort.Slice(group, func(i, j int) bool {
-				return Tanks[group[i].Class] < Tanks[group[j].Class]
+				return tanks[group[i].Class] < tanks[group[j].Class]
 			})
 		case classTypeMelee:
 			sort.Slice(group, func(i, j int) bool {
-				return MeleeDps[group[i].Class] < MeleeDps[group[j].Class]
+				return melee[group[i].Class] < melee[group[j].Class]
 			})
 		case classTypeCaster:
 			sort.Slice(group, func(i, j int) bool {
-				return CasterDps[group[i].Class] < CasterDps[group[j].Class]
+				return casters[group[i].Class] < casters[group[j].Class]
 			})
 		case classTypeHealer:
 			sort.Slice(group, func(i, j int) bool {
-				return Healers[group[i].Class] < Healers[group[j].Class]
+				return healers[group[i].Class] < healers[group[j].Class]
 			})
 		}
 	}
@@ -257,3 +343,13 @@ func GenSpread(toons []model.Character) map[string]int {
 
 	return spread
 }
+
+// ClassByAbbreviation looks up a class of the active ruleset from its abbreviation or full name, ignoring case
+func ClassByAbbreviation(s string) (int64, bool) {
+	for _, id := range ActiveRuleset().Classes {
+		if strings.EqualFold(ClassAbbreviationsMap[id], s) || strings.EqualFold(ClassChoiceMap[id], s) {
+			return id, true
+		}
+	}
+	return 0, false
+}
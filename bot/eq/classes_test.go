@@ -0,0 +1,81 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"sort"
+	"testing"
+)
+
+func TestEpicProgress(t *testing.T) {
+	cleric, _ := eq.ClassByAbbreviation("CLR")
+	milestones := []string{"Peacekeeper Staff", "Ring of the Vicious Maelstrom"}
+
+	tests := []struct {
+		step     int64
+		finished bool
+		expected string
+	}{
+		{0, false, "not started, 0/3"},
+		{1, false, "Peacekeeper Staff, 1/3"},
+		{2, false, "Ring of the Vicious Maelstrom, 2/3"},
+		{9, false, "Ring of the Vicious Maelstrom, 2/3"},
+		{2, true, "Water Sprinkler of Nem Ankh, 3/3"},
+		{0, true, "Water Sprinkler of Nem Ankh, 3/3"},
+	}
+
+	for _, test := range tests {
+		if got := eq.EpicProgress(cleric, milestones, test.step, test.finished); got != test.expected {
+			t.Errorf("step %d finished %t: expected %s, got %s", test.step, test.finished, test.expected, got)
+		}
+	}
+
+	// a class the officers have not cataloged yet only tracks the weapon
+	if got := eq.EpicProgress(cleric, nil, 1, false); got != "not started, 0/1" {
+		t.Errorf("expected an uncataloged epic to be not started, got %s", got)
+	}
+
+	for class := range eq.ClassChoiceMap {
+		steps := eq.EpicSteps(class, milestones)
+		if len(steps) != 3 || steps[len(steps)-1] != eq.EpicWeaponMap[class] {
+			t.Errorf("the last epic step of %s must be its weapon", eq.ClassChoiceMap[class])
+		}
+	}
+}
+
+func TestPowerScore(t *testing.T) {
+	tests := []struct {
+		c        model.Character
+		expected int64
+	}{
+		{model.Character{Level: 60}, 900},
+		{model.Character{Level: 60, AA: 30}, 930},
+		{model.Character{Level: 58, AA: 45}, 915},
+	}
+
+	for _, test := range tests {
+		if got := eq.PowerScore(test.c); got != test.expected {
+			t.Errorf("level %d with %d AA: expected %d, got %d", test.c.Level, test.c.AA, test.expected, got)
+		}
+	}
+}
+
+func TestSortToons(t *testing.T) {
+	group := []model.Character{
+		{Name: "Boxed", Level: 60, CharacterType: model.TypeBox},
+		{Name: "Low", Level: 55},
+		{Name: "Main", Level: 60, CharacterType: model.TypeMain},
+		{Name: "Tied", Level: 59, AA: 15, CharacterType: model.TypeMain},
+		{Name: "Veteran", Level: 59, AA: 30, CharacterType: model.TypeMain},
+	}
+
+	sort.Slice(group, eq.SortToons(group))
+
+	// highest power first, level breaks ties in power and mains come before boxes of equal standing
+	expected := []string{"Veteran", "Main", "Boxed", "Tied", "Low"}
+	for i, name := range expected {
+		if group[i].Name != name {
+			t.Errorf("position %d: expected %s, got %s", i, name, group[i].Name)
+		}
+	}
+}
@@ -0,0 +1,9 @@
+package eq
+
+// SortToons exposes the splitters class group ordering to the external tests
+var SortToons = sortToons
+
+// MeleeRanks exposes the melee role of a ruleset to the external tests
+func (r Ruleset) MeleeRanks() map[int64]int {
+	return r.roleRanks(meleeRanks)
+}
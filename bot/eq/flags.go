@@ -0,0 +1,22 @@
+package eq
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+)
+
+const MaxFlagKeyLength = 20
+
+var flagKeyMatch = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// NormalizeFlagKey lowercases a flag key and checks it is short and has no spaces so it can be typed in commands
+func NormalizeFlagKey(key string) (string, error) {
+	key = strings.ToLower(strings.TrimSpace(key))
+
+	if len(key) < 2 || len(key) > MaxFlagKeyLength || !flagKeyMatch.MatchString(key) {
+		return "", errors.New("flag keys must be 2 to 20 letters, numbers or dashes e.g. vp or sky-key")
+	}
+
+	return key, nil
+}
@@ -0,0 +1,23 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"testing"
+)
+
+func TestNormalizeFlagKey(t *testing.T) {
+	key, err := eq.NormalizeFlagKey(" Sky-Key ")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key != "sky-key" {
+		t.Errorf("expected sky-key, got %s", key)
+	}
+
+	for _, input := range []string{"v", "sleepers tomb", "vp-", "hate's", "averyveryverylongflagkey"} {
+		if _, err := eq.NormalizeFlagKey(input); err == nil {
+			t.Errorf("expected %s to be rejected", input)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package eq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// GuildMember is a single row of an /outputfile guild dump
+type GuildMember struct {
+	Name   string
+	Level  int64
+	Class  int64
+	Rank   string
+	Alt    bool
+	LastOn *time.Time
+	Zone   string
+	Notes  string
+}
+
+const (
+	guildColName = iota
+	guildColLevel
+	guildColClass
+	guildColRank
+	guildColAlt
+	guildColLastOn
+	guildColZone
+	guildColNotes
+)
+
+// ParseGuildDump reads the tab separated file written by /outputfile guild,
+// the columns are name, level, class, rank, alt flag, last on, zone and the public note,
+// anything after the public note such as personal notes is ignored
+func ParseGuildDump(r io.Reader) ([]GuildMember, error) {
+	var members []GuildMember
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		cols := strings.Split(text, "\t")
+		if len(cols) <= guildColAlt {
+			return nil, fmt.Errorf("line %d does not look like a guild dump", line)
+		}
+
+		name, err := NormalizeName(cols[guildColName])
+		if err != nil {
+			return nil, fmt.Errorf("line %d has an invalid name %s, %s", line, cols[guildColName], err.Error())
+		}
+
+		level, err := strconv.ParseInt(strings.TrimSpace(cols[guildColLevel]), 10, 64)
+		if err != nil || level < 1 || level > MaxLevel() {
+			return nil, fmt.Errorf("line %d has an invalid level %s, levels must be between 1 and %d", line, cols[guildColLevel], MaxLevel())
+		}
+
+		class, ok := ClassByAbbreviation(strings.ReplaceAll(strings.TrimSpace(cols[guildColClass]), " ", ""))
+		if !ok {
+			return nil, fmt.Errorf("line %d has an unknown class %s", line, cols[guildColClass])
+		}
+
+		member := GuildMember{
+			Name:  name,
+			Level: level,
+			Class: class,
+			Rank:  strings.TrimSpace(cols[guildColRank]),
+			Alt:   isAltFlag(cols[guildColAlt]),
+		}
+
+		if len(cols) > guildColLastOn {
+			if t, err := time.Parse("01/02/06", strings.TrimSpace(cols[guildColLastOn])); err == nil {
+				member.LastOn = &t
+			}
+		}
+
+		if len(cols) > guildColZone {
+			member.Zone = strings.TrimSpace(cols[guildColZone])
+		}
+
+		if len(cols) > guildColNotes {
+			member.Notes = strings.TrimSpace(cols[guildColNotes])
+		}
+
+		members = append(members, member)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func isAltFlag(s string) bool {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "a", "alt", "y", "yes", "1", "true":
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,56 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"strings"
+	"testing"
+)
+
+func TestParseGuildDump(t *testing.T) {
+	dump := "soANDso\t60\tCleric\tOfficer\t\t10/12/26\tPoK\tMain healer\tpersonal\r\n" +
+		"Boxalot\t52\tShadow Knight\tMember\tA\t10/01/26\n" +
+		"\n"
+
+	members, err := eq.ParseGuildDump(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(members) != 2 {
+		t.Fatalf("expected 2 members, got %d", len(members))
+	}
+
+	if members[0].Name != "Soandso" || members[0].Level != 60 || members[0].Alt || members[0].Rank != "Officer" {
+		t.Errorf("unexpected first member %+v", members[0])
+	}
+
+	if members[0].LastOn == nil || members[0].Zone != "PoK" || members[0].Notes != "Main healer" {
+		t.Errorf("expected last on, zone and the public note for %+v", members[0])
+	}
+
+	if members[1].Class != 5 || !members[1].Alt {
+		t.Errorf("expected an alt shadowknight, got %+v", members[1])
+	}
+}
+
+func TestParseGuildDumpRejectsUnknownClass(t *testing.T) {
+	if _, err := eq.ParseGuildDump(strings.NewReader("Someone\t60\tJester\tMember\t\n")); err == nil {
+		t.Error("expected an error for an unknown class")
+	}
+}
+
+func TestParseGuildDumpRejectsLevelsOverTheCap(t *testing.T) {
+	if _, err := eq.ParseGuildDump(strings.NewReader("Someone\t61\tCleric\tMember\t\n")); err == nil {
+		t.Errorf("expected an error for a level over %d", eq.MaxLevel())
+	}
+
+	if _, err := eq.ParseGuildDump(strings.NewReader("Someone\t0\tCleric\tMember\t\n")); err == nil {
+		t.Error("expected an error for level 0")
+	}
+}
+
+func TestParseGuildDumpRejectsInvalidNames(t *testing.T) {
+	if _, err := eq.ParseGuildDump(strings.NewReader("So-and-so\t60\tCleric\tMember\t\n")); err == nil {
+		t.Error("expected an error for a name with punctuation")
+	}
+}
@@ -0,0 +1,112 @@
+package eq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// InventoryItem is a single filled slot of an /outputfile inventory dump
+type InventoryItem struct {
+	Location string
+	Name     string
+	ItemId   int64
+	Count    int64
+	Slots    int64
+}
+
+// InBank reports whether the item is in the bank or shared bank rather than on the character
+func (r InventoryItem) InBank() bool {
+	return IsBankLocation(r.Location)
+}
+
+// IsBankLocation reports whether an inventory location is a bank or shared bank slot
+func IsBankLocation(location string) bool {
+	return strings.HasPrefix(location, "Bank") || strings.HasPrefix(location, "SharedBank")
+}
+
+const (
+	invColLocation = iota
+	invColName
+	invColId
+	invColCount
+	invColSlots
+)
+
+var inventoryFilenamePattern = regexp.MustCompile(`(?i)^(\w+)-inventory\.txt$`)
+
+// InventoryOwnerFromFilename returns the character an inventory dump belongs to, the game names them Name-Inventory.txt
+func InventoryOwnerFromFilename(filename string) (string, bool) {
+	match := inventoryFilenamePattern.FindStringSubmatch(filename)
+	if match == nil {
+		return "", false
+	}
+	return match[1], true
+}
+
+// ParseInventory reads the tab separated file written by /outputfile inventory,
+// the columns are location, name, id, count and slots. Empty slots are left out
+func ParseInventory(r io.Reader) ([]InventoryItem, error) {
+	var items []InventoryItem
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		cols := strings.Split(text, "\t")
+		if len(cols) <= invColCount {
+			return nil, fmt.Errorf("line %d does not look like an inventory dump", line)
+		}
+
+		if line == 1 && strings.EqualFold(cols[invColLocation], "Location") {
+			continue
+		}
+
+		name := strings.TrimSpace(cols[invColName])
+		if name == "" || name == "Empty" {
+			continue
+		}
+
+		id, err := strconv.ParseInt(strings.TrimSpace(cols[invColId]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d has an invalid item id %s", line, cols[invColId])
+		}
+
+		count, err := strconv.ParseInt(strings.TrimSpace(cols[invColCount]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d has an invalid count %s", line, cols[invColCount])
+		}
+
+		// stackable items report 0 when there is a single item
+		if count < 1 {
+			count = 1
+		}
+
+		var slots int64
+		if len(cols) > invColSlots {
+			slots, _ = strconv.ParseInt(strings.TrimSpace(cols[invColSlots]), 10, 64)
+		}
+
+		items = append(items, InventoryItem{
+			Location: strings.TrimSpace(cols[invColLocation]),
+			Name:     name,
+			ItemId:   id,
+			Count:    count,
+			Slots:    slots,
+		})
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return items, nil
+}
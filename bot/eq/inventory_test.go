@@ -0,0 +1,54 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const inventoryDump = "Location\tName\tID\tCount\tSlots\r\n" +
+	"Charm\tEmpty\t0\t0\t0\r\n" +
+	"Primary\tWater Sprinkler of Nem Ankh\t5532\t1\t0\r\n" +
+	"General1\tBackpack\t17005\t1\t8\r\n" +
+	"General1-Slot1\tBread Cakes\t13087\t20\t0\r\n" +
+	"Bank1-Slot2\tKey to Charasis\t20033\t1\t0\r\n" +
+	"SharedBank1\tSky Key\t20000\t0\t0\r\n"
+
+func TestParseInventory(t *testing.T) {
+	items, err := eq.ParseInventory(strings.NewReader(inventoryDump))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []eq.InventoryItem{
+		{Location: "Primary", Name: "Water Sprinkler of Nem Ankh", ItemId: 5532, Count: 1},
+		{Location: "General1", Name: "Backpack", ItemId: 17005, Count: 1, Slots: 8},
+		{Location: "General1-Slot1", Name: "Bread Cakes", ItemId: 13087, Count: 20},
+		{Location: "Bank1-Slot2", Name: "Key to Charasis", ItemId: 20033, Count: 1},
+		{Location: "SharedBank1", Name: "Sky Key", ItemId: 20000, Count: 1},
+	}
+
+	if !reflect.DeepEqual(items, expected) {
+		t.Errorf("expected %v, got %v", expected, items)
+	}
+
+	if items[2].InBank() || !items[3].InBank() || !items[4].InBank() {
+		t.Error("only bank and shared bank slots are in the bank")
+	}
+
+	if _, err = eq.ParseInventory(strings.NewReader("not an inventory")); err == nil {
+		t.Error("expected a file without columns to be rejected")
+	}
+}
+
+func TestInventoryOwnerFromFilename(t *testing.T) {
+	name, ok := eq.InventoryOwnerFromFilename("Clericbot-Inventory.txt")
+	if !ok || name != "Clericbot" {
+		t.Errorf("expected Clericbot, got %s", name)
+	}
+
+	if _, ok = eq.InventoryOwnerFromFilename("eqlog_Clericbot_test.txt"); ok {
+		t.Error("expected a log file name to be rejected")
+	}
+}
@@ -0,0 +1,29 @@
+package eq
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+const (
+	MinNameLength = 4
+	MaxNameLength = 15
+)
+
+// NormalizeName capitalises a character name the way EverQuest displays it and checks it follows the games naming rules
+func NormalizeName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+
+	if len(name) < MinNameLength || len(name) > MaxNameLength {
+		return "", fmt.Errorf("character names must be between %d and %d letters long", MinNameLength, MaxNameLength)
+	}
+
+	for _, r := range name {
+		if r > unicode.MaxASCII || !unicode.IsLetter(r) {
+			return "", fmt.Errorf("character names can only contain the letters A to Z")
+		}
+	}
+
+	return strings.ToUpper(name[:1]) + strings.ToLower(name[1:]), nil
+}
@@ -0,0 +1,30 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"testing"
+)
+
+func TestNormalizeName(t *testing.T) {
+	valid := map[string]string{
+		"soandso":         "Soandso",
+		" SOANDSO ":       "Soandso",
+		"Abcd":            "Abcd",
+		"Abcdefghijklmno": "Abcdefghijklmno",
+	}
+
+	for in, expected := range valid {
+		name, err := eq.NormalizeName(in)
+		if err != nil {
+			t.Errorf("%q: unexpected error %s", in, err)
+		} else if name != expected {
+			t.Errorf("%q: expected %s, got %s", in, expected, name)
+		}
+	}
+
+	for _, in := range []string{"Abc", "Abcdefghijklmnop", "So andso", "Soand5o", "Sôandso", "Soandso🙂"} {
+		if _, err := eq.NormalizeName(in); err == nil {
+			t.Errorf("%q: expected an error", in)
+		}
+	}
+}
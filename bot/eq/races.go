@@ -0,0 +1,141 @@
+package eq
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	RaceUnknown   = 0
+	raceHuman     = 1
+	raceBarbarian = 2
+	raceErudite   = 3
+	raceWoodElf   = 4
+	raceHighElf   = 5
+	raceDarkElf   = 6
+	raceHalfElf   = 7
+	raceDwarf     = 8
+	raceTroll     = 9
+	raceOgre      = 10
+	raceHalfling  = 11
+	raceGnome     = 12
+	raceIksar     = 13
+	raceVahShir   = 14
+	raceFroglok   = 15
+)
+
+var RaceChoiceMap = map[int64]string{
+	RaceUnknown:   "Unknown",
+	raceHuman:     "Human",
+	raceBarbarian: "Barbarian",
+	raceErudite:   "Erudite",
+	raceWoodElf:   "Wood Elf",
+	raceHighElf:   "High Elf",
+	raceDarkElf:   "Dark Elf",
+	raceHalfElf:   "Half Elf",
+	raceDwarf:     "Dwarf",
+	raceTroll:     "Troll",
+	raceOgre:      "Ogre",
+	raceHalfling:  "Halfling",
+	raceGnome:     "Gnome",
+	raceIksar:     "Iksar",
+	raceVahShir:   "Vah Shir",
+	raceFroglok:   "Froglok",
+}
+
+var RaceAbbreviationsMap = map[int64]string{
+	RaceUnknown:   "",
+	raceHuman:     "HUM",
+	raceBarbarian: "BAR",
+	raceErudite:   "ERU",
+	raceWoodElf:   "ELF",
+	raceHighElf:   "HIE",
+	raceDarkElf:   "DEF",
+	raceHalfElf:   "HEF",
+	raceDwarf:     "DWF",
+	raceTroll:     "TRL",
+	raceOgre:      "OGR",
+	raceHalfling:  "HFL",
+	raceGnome:     "GNM",
+	raceIksar:     "IKS",
+	raceVahShir:   "VAH",
+	raceFroglok:   "FRG",
+}
+
+// classRaces is the race and class compatibility matrix
+var classRaces = map[int64][]int64{
+	classWarrior:      {raceHuman, raceBarbarian, raceWoodElf, raceDarkElf, raceHalfElf, raceDwarf, raceTroll, raceOgre, raceHalfling, raceGnome, raceIksar, raceVahShir, raceFroglok},
+	classCleric:       {raceHuman, raceErudite, raceHighElf, raceDarkElf, raceDwarf, raceHalfling, raceGnome, raceFroglok},
+	classPaladin:      {raceHuman, raceErudite, raceHighElf, raceHalfElf, raceDwarf, raceHalfling, raceFroglok},
+	classRanger:       {raceHuman, raceWoodElf, raceHalfElf},
+	classShadowknight: {raceHuman, raceErudite, raceDarkElf, raceTroll, raceOgre, raceIksar, raceFroglok},
+	classDruid:        {raceHuman, raceWoodElf, raceHalfElf, raceHalfling},
+	classMonk:         {raceHuman, raceIksar, raceFroglok},
+	classBard:         {raceHuman, raceWoodElf, raceHalfElf, raceVahShir},
+	classRogue:        {raceHuman, raceBarbarian, raceWoodElf, raceDarkElf, raceHalfElf, raceDwarf, raceHalfling, raceGnome, raceVahShir, raceFroglok},
+	classShaman:       {raceBarbarian, raceTroll, raceOgre, raceIksar, raceVahShir, raceFroglok},
+	classNecromancer:  {raceHuman, raceErudite, raceDarkElf, raceGnome, raceIksar, raceFroglok},
+	classWizard:       {raceHuman, raceErudite, raceHighElf, raceDarkElf, raceGnome, raceFroglok},
+	classMagician:     {raceHuman, raceErudite, raceHighElf, raceDarkElf, raceGnome},
+	classEnchanter:    {raceHuman, raceErudite, raceHighElf, raceDarkElf, raceGnome},
+	classBeastlord:    {raceBarbarian, raceTroll, raceOgre, raceIksar, raceVahShir},
+	classBerserker:    {raceBarbarian, raceDwarf, raceTroll, raceOgre, raceVahShir},
+}
+
+// ValidRace reports whether the race can be the class, characters registered before races were tracked have an unknown race which is always allowed
+func ValidRace(class int64, race int64) bool {
+	if race == RaceUnknown {
+		return true
+	}
+
+	for _, r := range classRaces[class] {
+		if r == race {
+			return true
+		}
+	}
+	return false
+}
+
+// RacesForClass lists the races of the active ruleset that can be the class
+func RacesForClass(class int64) []int64 {
+	rs := ActiveRuleset()
+
+	var races []int64
+	for _, r := range classRaces[class] {
+		if rs.HasRace(r) {
+			races = append(races, r)
+		}
+	}
+	return races
+}
+
+// RaceChoiceString lists the races that can be the class
+func RaceChoiceString(class int64) string {
+	str := ""
+	for _, r := range RacesForClass(class) {
+		str += fmt.Sprintf("%d. %s\n", r, RaceChoiceMap[r])
+	}
+	return str
+}
+
+// RaceClassString describes a character as race and class e.g. High Elf Cleric, leaving out unknown races
+func RaceClassString(race int64, class int64) string {
+	if race == RaceUnknown {
+		return ClassChoiceMap[class]
+	}
+	return fmt.Sprintf("%s %s", RaceChoiceMap[race], ClassChoiceMap[class])
+}
+
+// RaceByName looks up a race from its abbreviation or full name, ignoring case and spaces
+func RaceByName(s string) (int64, bool) {
+	s = strings.ReplaceAll(s, " ", "")
+	for id, abbr := range RaceAbbreviationsMap {
+		if id == RaceUnknown {
+			continue
+		}
+		if strings.EqualFold(abbr, s) || strings.EqualFold(strings.ReplaceAll(RaceChoiceMap[id], " ", ""), s) {
+			return id, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,35 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"testing"
+)
+
+func TestValidRace(t *testing.T) {
+	ogre, _ := eq.RaceByName("Ogre")
+	highElf, _ := eq.RaceByName("high elf")
+	enchanter, _ := eq.ClassByAbbreviation("ENC")
+	shaman, _ := eq.ClassByAbbreviation("SHM")
+
+	if eq.ValidRace(enchanter, ogre) {
+		t.Error("ogres cannot be enchanters")
+	}
+
+	if !eq.ValidRace(enchanter, highElf) {
+		t.Error("high elves can be enchanters")
+	}
+
+	if !eq.ValidRace(shaman, ogre) {
+		t.Error("ogres can be shamans")
+	}
+
+	halfElf, _ := eq.RaceByName("half elf")
+	cleric, _ := eq.ClassByAbbreviation("CLR")
+	if eq.ValidRace(cleric, halfElf) {
+		t.Error("half elves cannot be clerics")
+	}
+
+	if !eq.ValidRace(shaman, eq.RaceUnknown) {
+		t.Error("an unknown race is always allowed")
+	}
+}
@@ -0,0 +1,81 @@
+package eq
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// RaidMember is a single row of an /outputfile raid dump
+type RaidMember struct {
+	Group int64
+	Name  string
+	Level int64
+	Class int64
+	Role  string
+}
+
+const (
+	raidColGroup = iota
+	raidColName
+	raidColLevel
+	raidColClass
+	raidColRole
+)
+
+// ParseRaidDump reads the tab separated RaidRoster file written by /outputfile raid,
+// the columns are group number, name, level, class and raid role
+func ParseRaidDump(r io.Reader) ([]RaidMember, error) {
+	var members []RaidMember
+
+	scanner := bufio.NewScanner(r)
+	line := 0
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		cols := strings.Split(text, "\t")
+		if len(cols) <= raidColClass {
+			return nil, fmt.Errorf("line %d does not look like a raid dump", line)
+		}
+
+		group, err := strconv.ParseInt(strings.TrimSpace(cols[raidColGroup]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d has an invalid group %s", line, cols[raidColGroup])
+		}
+
+		level, err := strconv.ParseInt(strings.TrimSpace(cols[raidColLevel]), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d has an invalid level %s", line, cols[raidColLevel])
+		}
+
+		class, ok := ClassByAbbreviation(strings.ReplaceAll(strings.TrimSpace(cols[raidColClass]), " ", ""))
+		if !ok {
+			return nil, fmt.Errorf("line %d has an unknown class %s", line, cols[raidColClass])
+		}
+
+		member := RaidMember{
+			Group: group,
+			Name:  strings.TrimSpace(cols[raidColName]),
+			Level: level,
+			Class: class,
+		}
+
+		if len(cols) > raidColRole {
+			member.Role = strings.TrimSpace(cols[raidColRole])
+		}
+
+		members = append(members, member)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
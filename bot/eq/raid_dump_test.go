@@ -0,0 +1,30 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"strings"
+	"testing"
+)
+
+func TestParseRaidDump(t *testing.T) {
+	dump := "1\tSoandso\t60\tCleric\tRaid Leader\t\t\r\n" +
+		"1\tBoxalot\t52\tShadow Knight\tGroup Leader\t\t\n" +
+		"0\tLooter\t58\tRogue\t\t\t\n"
+
+	members, err := eq.ParseRaidDump(strings.NewReader(dump))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(members) != 3 {
+		t.Fatalf("expected 3 members, got %d", len(members))
+	}
+
+	if members[0].Group != 1 || members[0].Name != "Soandso" || members[0].Role != "Raid Leader" {
+		t.Errorf("unexpected first member %+v", members[0])
+	}
+
+	if members[2].Group != 0 || members[2].Level != 58 {
+		t.Errorf("unexpected ungrouped member %+v", members[2])
+	}
+}
@@ -0,0 +1,112 @@
+package eq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	ResistFire    = 1
+	ResistCold    = 2
+	ResistMagic   = 3
+	ResistPoison  = 4
+	ResistDisease = 5
+)
+
+// MaxResist is the highest resist value accepted, anything above it is a typo
+const MaxResist = 1000
+
+// Resists lists the resist types in the order they are shown
+var Resists = []int64{ResistFire, ResistCold, ResistMagic, ResistPoison, ResistDisease}
+
+var ResistNameMap = map[int64]string{
+	ResistFire:    "Fire",
+	ResistCold:    "Cold",
+	ResistMagic:   "Magic",
+	ResistPoison:  "Poison",
+	ResistDisease: "Disease",
+}
+
+var ResistAbbreviationsMap = map[int64]string{
+	ResistFire:    "FR",
+	ResistCold:    "CR",
+	ResistMagic:   "MR",
+	ResistPoison:  "PR",
+	ResistDisease: "DR",
+}
+
+// ResistByName looks up a resist from its abbreviation or full name, ignoring case
+func ResistByName(s string) (int64, bool) {
+	for id, abbr := range ResistAbbreviationsMap {
+		if strings.EqualFold(abbr, s) || strings.EqualFold(ResistNameMap[id], s) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// ParseResists reads resist values written like FR:150,CR:100 or FR:150 CR:100
+func ParseResists(input string) (map[int64]int64, error) {
+	resists := make(map[int64]int64)
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	for _, pair := range fields {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("could not understand %s, resists look like FR:150", pair)
+		}
+
+		resist, ok := ResistByName(kv[0])
+		if !ok {
+			return nil, fmt.Errorf("%s is not a resist, use FR, CR, MR, PR or DR", kv[0])
+		}
+
+		n, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil || n < 0 || n > MaxResist {
+			return nil, fmt.Errorf("%s must be a number between 0 and %d", kv[0], MaxResist)
+		}
+		resists[resist] = n
+	}
+
+	return resists, nil
+}
+
+// FormatResists writes resists in the same form ParseResists reads
+func FormatResists(resists map[int64]int64) string {
+	var parts []string
+	for _, resist := range Resists {
+		if n, ok := resists[resist]; ok {
+			parts = append(parts, fmt.Sprintf("%s:%d", ResistAbbreviationsMap[resist], n))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ResistShortfall lists every required resist the character is below, resists that were never recorded count as missing
+func ResistShortfall(have map[int64]int64, need map[int64]int64) []string {
+	var short []string
+	for _, resist := range Resists {
+		min, ok := need[resist]
+		if !ok || min == 0 {
+			continue
+		}
+
+		n, recorded := have[resist]
+		switch {
+		case !recorded:
+			short = append(short, fmt.Sprintf("%s ?/%d", ResistAbbreviationsMap[resist], min))
+		case n < min:
+			short = append(short, fmt.Sprintf("%s %d/%d", ResistAbbreviationsMap[resist], n, min))
+		}
+	}
+	return short
+}
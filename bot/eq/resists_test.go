@@ -0,0 +1,40 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"reflect"
+	"testing"
+)
+
+func TestParseResists(t *testing.T) {
+	resists, err := eq.ParseResists("FR:150, cr:100 magic:90")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[int64]int64{eq.ResistFire: 150, eq.ResistCold: 100, eq.ResistMagic: 90}
+	if !reflect.DeepEqual(resists, expected) {
+		t.Errorf("expected %v, got %v", expected, resists)
+	}
+
+	for _, input := range []string{"FR150", "XR:10", "FR:-1", "FR:5000"} {
+		if _, err := eq.ParseResists(input); err == nil {
+			t.Errorf("expected %s to be rejected", input)
+		}
+	}
+}
+
+func TestResistShortfall(t *testing.T) {
+	have := map[int64]int64{eq.ResistFire: 120, eq.ResistCold: 200}
+	need := map[int64]int64{eq.ResistFire: 150, eq.ResistCold: 100, eq.ResistPoison: 80}
+
+	short := eq.ResistShortfall(have, need)
+	expected := []string{"FR 120/150", "PR ?/80"}
+	if !reflect.DeepEqual(short, expected) {
+		t.Errorf("expected %v, got %v", expected, short)
+	}
+
+	if eq.FormatResists(need) != "FR:150, CR:100, PR:80" {
+		t.Errorf("unexpected format %s", eq.FormatResists(need))
+	}
+}
@@ -0,0 +1,55 @@
+package eq
+
+import (
+	"eqRaidBot/db/model"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PrintRoster lists the mains, boxes and any alts of a set of characters with their class abbreviations
+func PrintRoster(toons []model.Character) string {
+	var (
+		mC, bC, aC                    int
+		boxString, mString, altString []string
+	)
+
+	for _, t := range toons {
+		name := fmt.Sprintf("(%s)%s", ClassAbbreviationsMap[t.Class], t.Name)
+		if t.Race != RaceUnknown {
+			name = fmt.Sprintf("(%s %s)%s", ClassAbbreviationsMap[t.Class], RaceAbbreviationsMap[t.Race], t.Name)
+		}
+		if t.AA > 0 {
+			name = fmt.Sprintf("%s %dAA", name, t.AA)
+		}
+
+		switch t.CharacterType {
+		case model.TypeBox:
+			boxString = append(boxString, name)
+			bC++
+		case model.TypeAlt:
+			altString = append(altString, name)
+			aC++
+		default:
+			mString = append(mString, name)
+			mC++
+		}
+	}
+
+	sort.Strings(boxString)
+	sort.Strings(mString)
+	sort.Strings(altString)
+
+	roster := fmt.Sprintf("**Mains** - %d: %s \n **Boxes** - %d: %s",
+		mC,
+		strings.Join(mString, ", "),
+		bC,
+		strings.Join(boxString, ", "))
+
+	// alts only turn up for events whose policy lets them in
+	if aC > 0 {
+		roster += fmt.Sprintf(" \n **Alts** - %d: %s", aC, strings.Join(altString, ", "))
+	}
+
+	return roster
+}
@@ -0,0 +1,186 @@
+package eq
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Ruleset describes what an expansion allows, the guild picks the one its server is on
+type Ruleset struct {
+	Expansion string
+	MaxLevel  int64
+	// MaxAA is the most alternate advancement points a character may register with
+	MaxAA int64
+	// Classes are the playable classes in the order they are offered during registration
+	Classes []int64
+	Races   []int64
+}
+
+var classicClasses = []int64{
+	classWarrior,
+	classMonk,
+	classRogue,
+	classPaladin,
+	classShadowknight,
+	classRanger,
+	classEnchanter,
+	classWizard,
+	classMagician,
+	classNecromancer,
+	classShaman,
+	classDruid,
+	classCleric,
+	classBard,
+}
+
+var withBeastlord = append(classicClasses[:len(classicClasses):len(classicClasses)], classBeastlord)
+
+var withBerserker = append(withBeastlord[:len(withBeastlord):len(withBeastlord)], classBerserker)
+
+var classicRaces = []int64{
+	raceHuman,
+	raceBarbarian,
+	raceErudite,
+	raceWoodElf,
+	raceHighElf,
+	raceDarkElf,
+	raceHalfElf,
+	raceDwarf,
+	raceTroll,
+	raceOgre,
+	raceHalfling,
+	raceGnome,
+}
+
+var withIksar = append(classicRaces[:len(classicRaces):len(classicRaces)], raceIksar)
+
+var withVahShir = append(withIksar[:len(withIksar):len(withIksar)], raceVahShir)
+
+var withFroglok = append(withVahShir[:len(withVahShir):len(withVahShir)], raceFroglok)
+
+// Rulesets lists the supported expansions oldest first
+var Rulesets = []Ruleset{
+	{Expansion: "classic", MaxLevel: 50, Classes: classicClasses, Races: classicRaces},
+	{Expansion: "kunark", MaxLevel: 60, Classes: classicClasses, Races: withIksar},
+	{Expansion: "velious", MaxLevel: 60, Classes: classicClasses, Races: withIksar},
+	// alternate advancement arrived with luclin, the caps are roughly what each expansion made available
+	{Expansion: "luclin", MaxLevel: 60, MaxAA: 150, Classes: withBeastlord, Races: withVahShir},
+	{Expansion: "pop", MaxLevel: 65, MaxAA: 300, Classes: withBeastlord, Races: withVahShir},
+	{Expansion: "ldon", MaxLevel: 65, MaxAA: 300, Classes: withBeastlord, Races: withFroglok},
+	{Expansion: "god", MaxLevel: 65, MaxAA: 300, Classes: withBerserker, Races: withFroglok},
+	{Expansion: "oow", MaxLevel: 70, MaxAA: 450, Classes: withBerserker, Races: withFroglok},
+}
+
+// DefaultExpansion matches what the bot supported before rulesets existed
+const DefaultExpansion = "velious"
+
+var (
+	activeMu sync.RWMutex
+	active   = mustRuleset(DefaultExpansion)
+)
+
+// RulesetByName finds the ruleset for an expansion, ignoring case
+func RulesetByName(expansion string) (Ruleset, bool) {
+	for _, rs := range Rulesets {
+		if strings.EqualFold(rs.Expansion, expansion) {
+			return rs, true
+		}
+	}
+	return Ruleset{}, false
+}
+
+func mustRuleset(expansion string) Ruleset {
+	rs, ok := RulesetByName(expansion)
+	if !ok {
+		panic(fmt.Sprintf("unknown expansion %s", expansion))
+	}
+	return rs
+}
+
+// ExpansionNames lists the expansions a guild can choose
+func ExpansionNames() string {
+	var names []string
+	for _, rs := range Rulesets {
+		names = append(names, rs.Expansion)
+	}
+	return strings.Join(names, ", ")
+}
+
+// ActiveRuleset is the ruleset of the guild the bot serves
+func ActiveRuleset() Ruleset {
+	activeMu.RLock()
+	defer activeMu.RUnlock()
+	return active
+}
+
+// SetRuleset switches the active ruleset to the given expansion
+func SetRuleset(expansion string) error {
+	rs, ok := RulesetByName(expansion)
+	if !ok {
+		return fmt.Errorf("%s is not a supported expansion, choose one of %s", expansion, ExpansionNames())
+	}
+
+	activeMu.Lock()
+	active = rs
+	activeMu.Unlock()
+
+	return nil
+}
+
+// MaxLevel is the level cap of the active ruleset
+func MaxLevel() int64 {
+	return ActiveRuleset().MaxLevel
+}
+
+// MaxAA is the AA cap of the active ruleset
+func MaxAA() int64 {
+	return ActiveRuleset().MaxAA
+}
+
+// SetMaxAA overrides the AA cap of every expansion that has alternate advancement for servers with their own cap,
+// expansions before luclin keep a cap of 0
+func SetMaxAA(max int64) {
+	activeMu.Lock()
+	defer activeMu.Unlock()
+
+	for i := range Rulesets {
+		if Rulesets[i].MaxAA > 0 {
+			Rulesets[i].MaxAA = max
+		}
+	}
+	if active.MaxAA > 0 {
+		active.MaxAA = max
+	}
+}
+
+// HasClass reports whether the class can be played under the ruleset
+func (r Ruleset) HasClass(class int64) bool {
+	for _, c := range r.Classes {
+		if c == class {
+			return true
+		}
+	}
+	return false
+}
+
+// roleRanks narrows a raid role table to the classes of the ruleset
+func (r Ruleset) roleRanks(role classRangeMap) classRangeMap {
+	ranks := make(classRangeMap)
+	for class, rank := range role {
+		if r.HasClass(class) {
+			ranks[class] = rank
+		}
+	}
+	return ranks
+}
+
+// HasRace reports whether the race can be played under the ruleset
+func (r Ruleset) HasRace(race int64) bool {
+	for _, c := range r.Races {
+		if c == race {
+			return true
+		}
+	}
+	return false
+}
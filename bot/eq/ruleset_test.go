@@ -0,0 +1,104 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"strings"
+	"testing"
+)
+
+func TestSetRuleset(t *testing.T) {
+	defer eq.SetRuleset(eq.DefaultExpansion)
+
+	if strings.Contains(eq.ClassChoiceString(), "Beastlord") {
+		t.Error("beastlords should not be available before luclin")
+	}
+
+	if err := eq.SetRuleset("Luclin"); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.Contains(eq.ClassChoiceString(), "15. Beastlord") {
+		t.Error("expected beastlords to be available in luclin")
+	}
+
+	if err := eq.SetRuleset("pop"); err != nil || eq.MaxLevel() != 65 {
+		t.Errorf("expected a level cap of 65 in planes of power, got %d", eq.MaxLevel())
+	}
+
+	if err := eq.SetRuleset("neverland"); err == nil {
+		t.Error("expected an error for an unknown expansion")
+	}
+}
+
+func TestSetMaxAA(t *testing.T) {
+	defer eq.SetRuleset(eq.DefaultExpansion)
+
+	caps := map[string]int64{"classic": 0, "velious": 0, "luclin": 150, "pop": 300, "oow": 450}
+	for expansion, expected := range caps {
+		if err := eq.SetRuleset(expansion); err != nil || eq.MaxAA() != expected {
+			t.Errorf("%s: expected an AA cap of %d, got %d", expansion, expected, eq.MaxAA())
+		}
+	}
+
+	originals := make([]int64, len(eq.Rulesets))
+	for i, rs := range eq.Rulesets {
+		originals[i] = rs.MaxAA
+	}
+	defer func() {
+		for i := range eq.Rulesets {
+			eq.Rulesets[i].MaxAA = originals[i]
+		}
+	}()
+
+	eq.SetMaxAA(500)
+	if eq.MaxAA() != 500 {
+		t.Errorf("expected an AA cap of 500, got %d", eq.MaxAA())
+	}
+
+	if err := eq.SetRuleset("velious"); err != nil || eq.MaxAA() != 0 {
+		t.Errorf("expected velious to keep an AA cap of 0, got %d", eq.MaxAA())
+	}
+
+	if err := eq.SetRuleset("luclin"); err != nil || eq.MaxAA() != 500 {
+		t.Errorf("expected the AA cap to follow a change of expansion, got %d", eq.MaxAA())
+	}
+}
+
+func TestRulesetClassesAndRoles(t *testing.T) {
+	defer eq.SetRuleset(eq.DefaultExpansion)
+
+	beastlord := int64(15)
+	if _, ok := eq.ClassByAbbreviation("BST"); ok {
+		t.Error("beastlords should not be recognised before luclin")
+	}
+
+	if _, ok := eq.ActiveRuleset().MeleeRanks()[beastlord]; ok {
+		t.Error("beastlords should not be melee before luclin")
+	}
+
+	if err := eq.SetRuleset("luclin"); err != nil {
+		t.Fatal(err)
+	}
+
+	if class, ok := eq.ClassByAbbreviation("BST"); !ok || class != beastlord {
+		t.Error("expected beastlords to be recognised in luclin")
+	}
+
+	if _, ok := eq.ActiveRuleset().MeleeRanks()[beastlord]; !ok {
+		t.Error("expected beastlords to be melee in luclin")
+	}
+}
+
+func TestFroglokFromLDoN(t *testing.T) {
+	defer eq.SetRuleset(eq.DefaultExpansion)
+
+	cleric, _ := eq.ClassByAbbreviation("CLR")
+	for expansion, playable := range map[string]bool{"pop": false, "ldon": true, "god": true, "oow": true} {
+		if err := eq.SetRuleset(expansion); err != nil {
+			t.Fatal(err)
+		}
+		if got := strings.Contains(eq.RaceChoiceString(cleric), "Froglok"); got != playable {
+			t.Errorf("%s: expected froglok clerics to be playable %t, got %t", expansion, playable, got)
+		}
+	}
+}
@@ -10,26 +10,35 @@ import (
 type Splitter struct {
 	characters []model.Character
 	usedMap    map[int64]bool
+	owners     map[string]string
+	ruleset    Ruleset
 	debug      bool
 }
 
+// NewSplitter splits the characters it is given, callers filter them by the events attendance policy first
 func NewSplitter(c []model.Character, debug bool) *Splitter {
-	// we only take mains and box's so filter out any alts here just in case they are passed to us
-	var characters []model.Character
-	for _, v := range c {
-		if v.CharacterType == model.TypeAlt {
-			continue
-		}
-		characters = append(characters, v)
-	}
-
 	return &Splitter{
-		characters: characters,
+		characters: c,
 		usedMap:    make(map[int64]bool),
+		owners:     make(map[string]string),
+		ruleset:    ActiveRuleset(),
 		debug:      debug,
 	}
 }
 
+// LinkOwners tells the splitter which discord accounts belong to the same player so their characters stay together,
+// owners maps an account to a key shared by all of the players accounts
+func (r *Splitter) LinkOwners(owners map[string]string) {
+	r.owners = owners
+}
+
+func (r *Splitter) ownerOf(c model.Character) string {
+	if key, ok := r.owners[c.CreatedBy]; ok {
+		return key
+	}
+	return c.CreatedBy
+}
+
 // sort the classes into groups
 // sort each group by level / aa / dkp
 // round robin class buckets off into raid groups
@@ -41,10 +50,11 @@ func NewSplitter(c []model.Character, debug bool) *Splitter {
 func (r *Splitter) Split(groupN int) ([][][]model.Character, []map[int64]int) {
 	charMap := make(map[string][]model.Character)
 	for _, k := range r.characters {
-		if _, ok := charMap[k.CreatedBy]; !ok {
-			charMap[k.CreatedBy] = []model.Character{k}
+		owner := r.ownerOf(k)
+		if _, ok := charMap[owner]; !ok {
+			charMap[owner] = []model.Character{k}
 		} else {
-			charMap[k.CreatedBy] = append(charMap[k.CreatedBy], k)
+			charMap[owner] = append(charMap[owner], k)
 		}
 	}
 
@@ -235,12 +245,31 @@ func (r *Splitter) bardsAvailable(classGroups map[string][]model.Character) bool
 	return false
 }
 
+// classOrder is the order classes are dealt into splits, the rulesets classes come first
+// followed by any class it does not know so no character is left out
+func (r *Splitter) classOrder(classGroups map[int64][]model.Character) []int64 {
+	order := append([]int64{}, r.ruleset.Classes...)
+
+	var extra []int64
+	for class := range classGroups {
+		if !r.ruleset.HasClass(class) {
+			extra = append(extra, class)
+		}
+	}
+
+	sort.Slice(extra, func(i, j int) bool {
+		return extra[i] < extra[j]
+	})
+
+	return append(order, extra...)
+}
+
 func (r *Splitter) getSplits(groupN int, classGroups map[int64][]model.Character, charMap map[string][]model.Character) [][]model.Character {
 	splits := make([][]model.Character, groupN)
 	var (
 		top       model.Character
-		classN    int64 = 1
-		currSplit       = 0
+		classN    int64
+		currSplit = 0
 	)
 
 	// handle any bots that exist
@@ -268,21 +297,16 @@ func (r *Splitter) getSplits(groupN int, classGroups map[int64][]model.Character
 
 	currSplit = 0
 
-	for {
-		if classN > 14 {
-			break
-		}
-
+	for _, classN = range r.classOrder(classGroups) {
 		if _, ok := classGroups[classN]; !ok {
-			classN++
 			continue
 		}
 
 		for len(classGroups[classN]) > 0 {
 			top, classGroups[classN] = classGroups[classN][0], classGroups[classN][1:]
 			// has a bot
-			if _, ok := charMap[top.CreatedBy]; ok {
-				splits[currSplit] = append(splits[currSplit], charMap[top.CreatedBy]...)
+			if _, ok := charMap[r.ownerOf(top)]; ok {
+				splits[currSplit] = append(splits[currSplit], charMap[r.ownerOf(top)]...)
 			} else {
 				splits[currSplit] = append(splits[currSplit], top)
 			}
@@ -293,7 +317,6 @@ func (r *Splitter) getSplits(groupN int, classGroups map[int64][]model.Character
 				currSplit++
 			}
 		}
-		classN++
 	}
 
 	return splits
@@ -1 +1,89 @@
 package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"eqRaidBot/db/model"
+	"fmt"
+	"testing"
+)
+
+// raiders makes one level 60 main of each class in the active ruleset for every round, each with its own owner
+func raiders(rounds int) []model.Character {
+	var toons []model.Character
+	for i := 0; i < rounds; i++ {
+		for _, class := range eq.ActiveRuleset().Classes {
+			id := int64(len(toons) + 1)
+			toons = append(toons, model.Character{
+				Id:            id,
+				Name:          fmt.Sprintf("Raider%d", id),
+				Class:         class,
+				Level:         60,
+				CharacterType: model.TypeMain,
+				CreatedBy:     fmt.Sprintf("user%d", id),
+			})
+		}
+	}
+	return toons
+}
+
+// splitOf finds which raid each character was placed in and fails when one is missing or placed twice
+func splitOf(t *testing.T, toons []model.Character, splits [][][]model.Character) map[int64]int {
+	placed := make(map[int64]int)
+	for i, split := range splits {
+		for _, group := range split {
+			for _, c := range group {
+				if _, ok := placed[c.Id]; ok {
+					t.Errorf("%s was placed more than once", c.Name)
+				}
+				placed[c.Id] = i
+			}
+		}
+	}
+
+	for _, c := range toons {
+		if _, ok := placed[c.Id]; !ok {
+			t.Errorf("%s was left out of the splits", c.Name)
+		}
+	}
+
+	return placed
+}
+
+func TestSplitKeepsLinkedAccountsTogether(t *testing.T) {
+	toons := raiders(2)
+
+	cleric, _ := eq.ClassByAbbreviation("CLR")
+	enchanter, _ := eq.ClassByAbbreviation("ENC")
+	main := model.Character{Id: 100, Name: "Mainly", Class: cleric, Level: 60, CharacterType: model.TypeMain, CreatedBy: "home"}
+	box := model.Character{Id: 101, Name: "Boxly", Class: enchanter, Level: 60, CharacterType: model.TypeBox, CreatedBy: "laptop"}
+	toons = append(toons, main, box)
+
+	splitter := eq.NewSplitter(toons, false)
+	splitter.LinkOwners(map[string]string{"home": "player1", "laptop": "player1"})
+	splits, _ := splitter.Split(2)
+
+	placed := splitOf(t, toons, splits)
+	if placed[main.Id] != placed[box.Id] {
+		t.Errorf("expected %s and %s to be in the same raid, got raids %d and %d", main.Name, box.Name, placed[main.Id]+1, placed[box.Id]+1)
+	}
+}
+
+func TestSplitUsesRulesetClasses(t *testing.T) {
+	defer eq.SetRuleset(eq.DefaultExpansion)
+	if err := eq.SetRuleset("luclin"); err != nil {
+		t.Fatal(err)
+	}
+
+	beastlord, _ := eq.ClassByAbbreviation("BST")
+	toons := raiders(2)
+
+	splits, stats := eq.NewSplitter(toons, false).Split(2)
+	splitOf(t, toons, splits)
+
+	// a class the ruleset deals out is spread across the raids like any other
+	for i, s := range stats {
+		if s[beastlord] != 1 {
+			t.Errorf("expected one beastlord in raid %d, got %d", i+1, s[beastlord])
+		}
+	}
+}
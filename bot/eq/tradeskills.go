@@ -0,0 +1,130 @@
+package eq
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+const (
+	SkillBaking        = 1
+	SkillBlacksmithing = 2
+	SkillBrewing       = 3
+	SkillFletching     = 4
+	SkillJewelcraft    = 5
+	SkillPottery       = 6
+	SkillTailoring     = 7
+	SkillAlchemy       = 8
+	SkillPoisonMaking  = 9
+	SkillTinkering     = 10
+	SkillResearch      = 11
+	SkillFishing       = 12
+)
+
+// MaxTradeskill is the highest trained tradeskill value accepted
+const MaxTradeskill = 300
+
+// Tradeskills lists the tradeskills in the order they are shown
+var Tradeskills = []int64{
+	SkillBaking,
+	SkillBlacksmithing,
+	SkillBrewing,
+	SkillFletching,
+	SkillJewelcraft,
+	SkillPottery,
+	SkillTailoring,
+	SkillAlchemy,
+	SkillPoisonMaking,
+	SkillTinkering,
+	SkillResearch,
+	SkillFishing,
+}
+
+var TradeskillNameMap = map[int64]string{
+	SkillBaking:        "Baking",
+	SkillBlacksmithing: "Blacksmithing",
+	SkillBrewing:       "Brewing",
+	SkillFletching:     "Fletching",
+	SkillJewelcraft:    "Jewelcraft",
+	SkillPottery:       "Pottery",
+	SkillTailoring:     "Tailoring",
+	SkillAlchemy:       "Alchemy",
+	SkillPoisonMaking:  "Poison Making",
+	SkillTinkering:     "Tinkering",
+	SkillResearch:      "Research",
+	SkillFishing:       "Fishing",
+}
+
+// tradeskillAliases are the short names members use for tradeskills
+var tradeskillAliases = map[string]int64{
+	"smithing": SkillBlacksmithing,
+	"bs":       SkillBlacksmithing,
+	"jc":       SkillJewelcraft,
+	"jewelry":  SkillJewelcraft,
+	"poison":   SkillPoisonMaking,
+	"fletch":   SkillFletching,
+	"tailor":   SkillTailoring,
+	"tinker":   SkillTinkering,
+	"brew":     SkillBrewing,
+	"bake":     SkillBaking,
+}
+
+// TradeskillByName looks up a tradeskill from its name or a common short name, ignoring case and spaces
+func TradeskillByName(s string) (int64, bool) {
+	s = strings.ToLower(strings.ReplaceAll(s, " ", ""))
+	if id, ok := tradeskillAliases[s]; ok {
+		return id, true
+	}
+
+	for id, name := range TradeskillNameMap {
+		if strings.EqualFold(strings.ReplaceAll(name, " ", ""), s) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+// ParseTradeskills reads tradeskill values written like smithing:250,tailoring:180 or smithing:250 tailoring:180
+func ParseTradeskills(input string) (map[int64]int64, error) {
+	skills := make(map[int64]int64)
+
+	fields := strings.FieldsFunc(input, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+
+	for _, pair := range fields {
+		kv := strings.SplitN(pair, ":", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("could not understand %s, tradeskills look like smithing:250", pair)
+		}
+
+		skill, ok := TradeskillByName(kv[0])
+		if !ok {
+			return nil, fmt.Errorf("%s is not a tradeskill", kv[0])
+		}
+
+		n, err := strconv.ParseInt(kv[1], 10, 64)
+		if err != nil || n < 0 || n > MaxTradeskill {
+			return nil, fmt.Errorf("%s must be a number between 0 and %d", kv[0], MaxTradeskill)
+		}
+		skills[skill] = n
+	}
+
+	return skills, nil
+}
+
+// FormatTradeskills lists the trained tradeskills, skills at 0 are left out
+func FormatTradeskills(skills map[int64]int64) string {
+	var parts []string
+	for _, skill := range Tradeskills {
+		if n := skills[skill]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", TradeskillNameMap[skill], n))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,29 @@
+package eq_test
+
+import (
+	"eqRaidBot/bot/eq"
+	"reflect"
+	"testing"
+)
+
+func TestParseTradeskills(t *testing.T) {
+	skills, err := eq.ParseTradeskills("smithing:250, Jewelcraft:180 poisonmaking:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := map[int64]int64{eq.SkillBlacksmithing: 250, eq.SkillJewelcraft: 180, eq.SkillPoisonMaking: 0}
+	if !reflect.DeepEqual(skills, expected) {
+		t.Errorf("expected %v, got %v", expected, skills)
+	}
+
+	if eq.FormatTradeskills(skills) != "Blacksmithing 250, Jewelcraft 180" {
+		t.Errorf("unexpected format %s", eq.FormatTradeskills(skills))
+	}
+
+	for _, input := range []string{"smithing250", "knitting:10", "tailoring:301"} {
+		if _, err := eq.ParseTradeskills(input); err == nil {
+			t.Errorf("expected %s to be rejected", input)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+// Package eqlog reads EverQuest client log files (eqlog_<name>_<server>.txt)
+package eqlog
+
+import (
+	"bufio"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Kind int
+
+const (
+	KindWho = Kind(iota + 1)
+	KindRaidJoin
+	KindRaidLeave
+	KindSlain
+	KindLoot
+	KindGuildChat
+)
+
+// Line is a log line the parser understood, Name is always the player the line is about
+type Line struct {
+	Time time.Time
+	Kind Kind
+	Name string
+	// Target is the mob that died for kills and the item for loot
+	Target string
+	// Text is the message for guild chat
+	Text string
+	// Level, Class, Race and Guild are only known from /who output, Class is empty for anonymous players
+	Level int64
+	Class string
+	Race  string
+	Guild string
+}
+
+const timeLayout = "Mon Jan 02 15:04:05 2006"
+
+var (
+	linePattern      = regexp.MustCompile(`^\[(\w{3} \w{3} \d{2} \d{2}:\d{2}:\d{2} \d{4})\] (.*)$`)
+	whoPattern       = regexp.MustCompile(`^(?:AFK |LFG |<LINKDEAD>)*\[(\d+) ([A-Za-z ]+)\] (\w+)(?: \(([A-Za-z ]+)\))?(?: +<([^>]+)>)?`)
+	whoAnonPattern   = regexp.MustCompile(`^(?:AFK |LFG |<LINKDEAD>)*\[ANONYMOUS\] (\w+)(?: +<([^>]+)>)?`)
+	raidJoinPattern  = regexp.MustCompile(`^(\w+) (?:has|have) joined the raid\.$`)
+	raidLeavePattern = regexp.MustCompile(`^(\w+) (?:has|have) left the raid\.$`)
+	slainByPattern   = regexp.MustCompile(`^(.+) has been slain by (\w+)!$`)
+	youSlainPattern  = regexp.MustCompile(`^You have slain (.+)!$`)
+	lootPattern      = regexp.MustCompile(`^--(\w+) (?:has|have) looted (?:a |an |the )?(.+?)\.--$`)
+	guildPattern     = regexp.MustCompile(`^(\w+) tells the guild, '(.*)'$`)
+	youGuildPattern  = regexp.MustCompile(`^You say to your guild, '(.*)'$`)
+	filenamePattern  = regexp.MustCompile(`(?i)^eqlog_(\w+)_(\w+)\.txt$`)
+)
+
+// OwnerFromFilename returns the character and server a log file belongs to
+func OwnerFromFilename(filename string) (string, string, bool) {
+	match := filenamePattern.FindStringSubmatch(filename)
+	if match == nil {
+		return "", "", false
+	}
+	return match[1], match[2], true
+}
+
+// Parse reads every line it understands, owner replaces "You" so lines always name a player.
+// Log times have no zone so they are read in loc, which should be the zone of the players computer
+func Parse(r io.Reader, owner string, loc *time.Location) ([]Line, error) {
+	var lines []Line
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		match := linePattern.FindStringSubmatch(strings.TrimRight(scanner.Text(), "\r"))
+		if match == nil {
+			continue
+		}
+
+		t, err := time.ParseInLocation(timeLayout, match[1], loc)
+		if err != nil {
+			continue
+		}
+
+		if l, ok := parseMessage(match[2], owner); ok {
+			l.Time = t
+			lines = append(lines, l)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	return lines, nil
+}
+
+func parseMessage(msg string, owner string) (Line, bool) {
+	if m := whoPattern.FindStringSubmatch(msg); m != nil {
+		level, _ := strconv.ParseInt(m[1], 10, 64)
+		return Line{Kind: KindWho, Level: level, Class: m[2], Name: m[3], Race: m[4], Guild: m[5]}, true
+	}
+
+	if m := whoAnonPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindWho, Name: m[1], Guild: m[2]}, true
+	}
+
+	if m := raidJoinPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindRaidJoin, Name: player(m[1], owner)}, true
+	}
+
+	if m := raidLeavePattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindRaidLeave, Name: player(m[1], owner)}, true
+	}
+
+	if m := slainByPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindSlain, Name: m[2], Target: m[1]}, true
+	}
+
+	if m := youSlainPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindSlain, Name: owner, Target: m[1]}, true
+	}
+
+	if m := lootPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindLoot, Name: player(m[1], owner), Target: m[2]}, true
+	}
+
+	if m := guildPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindGuildChat, Name: m[1], Text: m[2]}, true
+	}
+
+	if m := youGuildPattern.FindStringSubmatch(msg); m != nil {
+		return Line{Kind: KindGuildChat, Name: owner, Text: m[1]}, true
+	}
+
+	return Line{}, false
+}
+
+// IsKill reports whether a slain line is a mob kill rather than the death of a known player,
+// "Soandso has been slain by Vox!" parses the same way as a kill, players holds lower case character names
+func (l Line) IsKill(players map[string]bool) bool {
+	return l.Kind == KindSlain && !players[strings.ToLower(l.Target)]
+}
+
+func player(name string, owner string) string {
+	if name == "You" {
+		return owner
+	}
+	return name
+}
@@ -0,0 +1,92 @@
+package eqlog_test
+
+import (
+	"eqRaidBot/bot/eqlog"
+	"strings"
+	"testing"
+	"time"
+)
+
+const sample = `[Mon Oct 19 20:00:01 2026] Players on EverQuest:
+[Mon Oct 19 20:00:01 2026] ---------------------------
+[Mon Oct 19 20:00:01 2026] [60 Cleric] Soandso (High Elf) <Divine Order>
+[Mon Oct 19 20:00:01 2026] AFK [ANONYMOUS] Sneaky  <Divine Order>
+[Mon Oct 19 20:00:01 2026] There are 2 players in Nagafen's Lair.
+[Mon Oct 19 20:01:10 2026] Boxalot has joined the raid.
+[Mon Oct 19 20:02:00 2026] You have joined the raid.
+[Mon Oct 19 20:30:45 2026] Lord Nagafen has been slain by Boxalot!
+[Mon Oct 19 20:31:02 2026] --You have looted a Cloak of Flames.--
+[Mon Oct 19 20:31:05 2026] --Boxalot has looted an Orb of Exploration.--
+[Mon Oct 19 20:32:00 2026] Soandso tells the guild, 'grats'
+[Mon Oct 19 20:33:00 2026] You say to your guild, 'thanks'
+[Mon Oct 19 20:40:00 2026] Boxalot has left the raid.
+[Mon Oct 19 20:41:00 2026] You have slain a lava drake!
+[Mon Oct 19 20:42:00 2026] You begin casting Complete Healing.
+`
+
+func TestParse(t *testing.T) {
+	lines, err := eqlog.Parse(strings.NewReader(sample), "Healsalot", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []eqlog.Line{
+		{Kind: eqlog.KindWho, Name: "Soandso", Level: 60, Class: "Cleric", Race: "High Elf", Guild: "Divine Order"},
+		{Kind: eqlog.KindWho, Name: "Sneaky", Guild: "Divine Order"},
+		{Kind: eqlog.KindRaidJoin, Name: "Boxalot"},
+		{Kind: eqlog.KindRaidJoin, Name: "Healsalot"},
+		{Kind: eqlog.KindSlain, Name: "Boxalot", Target: "Lord Nagafen"},
+		{Kind: eqlog.KindLoot, Name: "Healsalot", Target: "Cloak of Flames"},
+		{Kind: eqlog.KindLoot, Name: "Boxalot", Target: "Orb of Exploration"},
+		{Kind: eqlog.KindGuildChat, Name: "Soandso", Text: "grats"},
+		{Kind: eqlog.KindGuildChat, Name: "Healsalot", Text: "thanks"},
+		{Kind: eqlog.KindRaidLeave, Name: "Boxalot"},
+		{Kind: eqlog.KindSlain, Name: "Healsalot", Target: "a lava drake"},
+	}
+
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %d: %+v", len(expected), len(lines), lines)
+	}
+
+	for i, l := range lines {
+		l.Time = time.Time{}
+		if l != expected[i] {
+			t.Errorf("line %d: expected %+v, got %+v", i, expected[i], l)
+		}
+	}
+
+	if !lines[0].Time.Equal(time.Date(2026, 10, 19, 20, 0, 1, 0, time.UTC)) {
+		t.Errorf("unexpected time %s", lines[0].Time)
+	}
+}
+
+func TestOwnerFromFilename(t *testing.T) {
+	name, server, ok := eqlog.OwnerFromFilename("eqlog_Healsalot_P1999Green.txt")
+	if !ok || name != "Healsalot" || server != "P1999Green" {
+		t.Errorf("unexpected owner %s on %s", name, server)
+	}
+}
+
+func TestIsKill(t *testing.T) {
+	log := "[Mon Oct 19 20:30:45 2026] Lord Nagafen has been slain by Boxalot!\n" +
+		"[Mon Oct 19 20:35:12 2026] Soandso has been slain by Vox!\n"
+
+	lines, err := eqlog.Parse(strings.NewReader(log), "Healsalot", time.UTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 slain lines, got %+v", lines)
+	}
+
+	players := map[string]bool{"soandso": true, "boxalot": true}
+
+	if !lines[0].IsKill(players) {
+		t.Errorf("expected %s to be a kill", lines[0].Target)
+	}
+
+	if lines[1].IsKill(players) {
+		t.Errorf("expected the death of %s not to be a kill", lines[1].Target)
+	}
+}
@@ -24,12 +24,42 @@ func (a *EventWatcher) Run(stop <-chan struct{}, d time.Duration) {
 			log.Println("Stopping event watcher...")
 			return
 		case <-t.C:
-			err := a.checkEvents()
+			err := a.progressEvents()
 			if err != nil {
 				log.Printf(err.Error())
 			}
+
+			err = a.checkEvents()
+			if err != nil {
+				log.Printf(err.Error())
+			}
+		}
+	}
+}
+
+// progressEvents completes scheduled events whose time has passed without an officer starting them,
+// started events run until an officer ends them so long raids are not closed while check in is still going
+func (a *EventWatcher) progressEvents() error {
+	eventProvider := model.Event{}
+	events, err := eventProvider.GetWhereStatus(a.db, []int64{model.EventStatusScheduled})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for _, e := range events {
+		if !e.EndTime().Before(now) {
+			continue
+		}
+
+		if err = e.UpdateStatus(a.db, model.EventStatusCompleted); err != nil {
+			log.Printf(err.Error())
+			continue
 		}
+		log.Printf("event %d is now %s", e.Id, model.EventStatusMap[model.EventStatusCompleted])
 	}
+
+	return nil
 }
 
 func (a *EventWatcher) checkEvents() error {
@@ -53,14 +83,37 @@ func (a *EventWatcher) checkEvents() error {
 					Description:  e.Description,
 					EventTime:    e.EventTime.Add(24 * 7 * time.Hour),
 					IsRepeatable: true,
+					MaxAttendees: e.MaxAttendees,
+					MinLevel:     e.MinLevel,
+					AllowedTypes: e.AllowedTypes,
+					SplitCount:   e.SplitCount,
+					Duration:     e.Duration,
 					CreatedBy:    e.CreatedBy,
 				}
 
-				err = event.Save(a.db)
-				if err != nil {
+				// the original keeps repeating and nothing is copied until the renewal has a real id
+				if err = event.Save(a.db); err != nil {
+					log.Printf(err.Error())
 					continue
 				}
 
+				// titles are no longer unique so the old event must stop repeating or it is renewed every run
+				if err = e.StopRepeating(a.db); err != nil {
+					log.Printf(err.Error())
+				}
+
+				if err = a.copyClassCaps(e.Id, event.Id); err != nil {
+					log.Printf(err.Error())
+				}
+
+				if err = a.copyResists(e.Id, event.Id); err != nil {
+					log.Printf(err.Error())
+				}
+
+				if err = a.copyFlags(e.Id, event.Id); err != nil {
+					log.Printf(err.Error())
+				}
+
 				seen[e.Title] = true
 			}
 		}
@@ -68,6 +121,65 @@ func (a *EventWatcher) checkEvents() error {
 	return nil
 }
 
+func (a *EventWatcher) copyClassCaps(fromId, toId int64) error {
+	if toId == 0 {
+		return nil
+	}
+
+	capProvider := model.EventClassCap{}
+	caps, err := capProvider.GetForEvent(a.db, fromId)
+	if err != nil {
+		return err
+	}
+
+	if len(caps) == 0 {
+		return nil
+	}
+
+	return capProvider.ReplaceForEvent(a.db, toId, caps)
+}
+
+func (a *EventWatcher) copyResists(fromId, toId int64) error {
+	if toId == 0 {
+		return nil
+	}
+
+	resistProvider := model.EventResist{}
+	resists, err := resistProvider.GetForEvent(a.db, fromId)
+	if err != nil {
+		return err
+	}
+
+	if len(resists) == 0 {
+		return nil
+	}
+
+	return resistProvider.ReplaceForEvent(a.db, toId, resists)
+}
+
+func (a *EventWatcher) copyFlags(fromId, toId int64) error {
+	if toId == 0 {
+		return nil
+	}
+
+	flagProvider := model.EventFlag{}
+	flags, err := flagProvider.GetForEvent(a.db, fromId)
+	if err != nil {
+		return err
+	}
+
+	if len(flags) == 0 {
+		return nil
+	}
+
+	var flagIds []int64
+	for _, f := range flags {
+		flagIds = append(flagIds, f.Id)
+	}
+
+	return flagProvider.ReplaceForEvent(a.db, toId, flagIds)
+}
+
 func (a *EventWatcher) needsRenewal(e model.Event) bool {
 	return e.EventTime.Before(time.Now()) && e.IsRepeatable
 }
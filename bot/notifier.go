@@ -0,0 +1,67 @@
+package bot
+
+import (
+	"eqRaidBot/db/model"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/bwmarrin/discordgo"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Notifier delivers queued notifications to users as direct messages
+type Notifier struct {
+	db      *pgxpool.Pool
+	session *discordgo.Session
+}
+
+func NewNotifier(db *pgxpool.Pool, s *discordgo.Session) *Notifier {
+	return &Notifier{
+		db:      db,
+		session: s,
+	}
+}
+
+func (a *Notifier) Run(stop <-chan struct{}, d time.Duration) {
+	t := time.NewTicker(d)
+	for {
+		select {
+		case <-stop:
+			t.Stop()
+			log.Println("Stopping notifier...")
+			return
+		case <-t.C:
+			err := a.send()
+			if err != nil {
+				log.Printf(err.Error())
+			}
+		}
+	}
+}
+
+func (a *Notifier) send() error {
+	notificationProvider := model.Notification{}
+	pending, err := notificationProvider.GetPending(a.db)
+	if err != nil {
+		return err
+	}
+
+	for _, n := range pending {
+		c, err := a.session.UserChannelCreate(n.UserId)
+		if err != nil {
+			return err
+		}
+
+		_, err = a.session.ChannelMessageSend(c.ID, fmt.Sprintf(">>>%s", n.Message))
+		if err != nil {
+			return err
+		}
+
+		if err = n.MarkSent(a.db); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
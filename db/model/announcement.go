@@ -0,0 +1,79 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Announcement tracks the discord message that was posted for an event so it can be edited in place
+type Announcement struct {
+	EventId   int64
+	ChannelId string
+	MessageId string
+	Content   string
+	Closed    bool
+	UpdatedAt time.Time
+	CreatedAt time.Time
+}
+
+func (r *Announcement) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `INSERT INTO event_announcements
+	(event_id, channel_id, message_id, content, closed, updated_at)
+	VALUES ($1, $2, $3, $4, $5, NOW());`,
+		r.EventId,
+		r.ChannelId,
+		r.MessageId,
+		r.Content,
+		r.Closed,
+	)
+
+	return err
+}
+
+func (r *Announcement) Update(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE event_announcements
+SET channel_id=$1, message_id=$2, content=$3, closed=$4, updated_at=NOW()
+WHERE event_id=$5;`,
+		r.ChannelId,
+		r.MessageId,
+		r.Content,
+		r.Closed,
+		r.EventId,
+	)
+
+	return err
+}
+
+func (r *Announcement) GetOpen(db *pgxpool.Pool) ([]Announcement, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var announcements []Announcement
+	q := `SELECT * FROM event_announcements WHERE closed = false;`
+	if err = pgxscan.Select(context.Background(), db, &announcements, q); err != nil {
+		return nil, err
+	}
+
+	return announcements, nil
+}
@@ -12,9 +12,12 @@ import (
 )
 
 type Attendance struct {
+	Id          int64
 	EventId     int64
 	CharacterId int64
 	Withdrawn   bool
+	Waitlisted  bool
+	Attended    bool
 	CreatedAt   time.Time
 	UpdatedAt   time.Time
 }
@@ -27,15 +30,17 @@ func (r *Attendance) Save(db *pgxpool.Pool) error {
 
 	defer conn.Release()
 
-	conn.QueryRow(context.Background(), `INSERT INTO attendance 
-	(character_id, event_id, withdrawn, updated_at) 
-	VALUES ($1, $2, $3, NOW());`,
+	_, err = conn.Exec(context.Background(), `INSERT INTO attendance 
+	(character_id, event_id, withdrawn, waitlisted, attended, updated_at) 
+	VALUES ($1, $2, $3, $4, $5, NOW());`,
 		r.CharacterId,
 		r.EventId,
 		r.Withdrawn,
+		r.Waitlisted,
+		r.Attended,
 	)
 
-	return nil
+	return err
 }
 
 func (r *Attendance) Update(db *pgxpool.Pool) error {
@@ -46,18 +51,47 @@ func (r *Attendance) Update(db *pgxpool.Pool) error {
 
 	defer conn.Release()
 
-	conn.QueryRow(context.Background(), `UPDATE attendance 
-SET withdrawn=$1, updated_at=NOW() 
-WHERE event_id=$2 AND character_id=$3;`,
+	_, err = conn.Exec(context.Background(), `UPDATE attendance 
+SET withdrawn=$1, waitlisted=$2, attended=$3, updated_at=NOW() 
+WHERE event_id=$4 AND character_id=$5;`,
 		r.Withdrawn,
+		r.Waitlisted,
+		r.Attended,
 		r.EventId,
 		r.CharacterId,
 	)
 
-	return nil
+	return err
 }
 
+// SaveOrSignBack signs a character up for an event, a row left behind by withdrawing is signed back up in place
+func (r *Attendance) SaveOrSignBack(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `INSERT INTO attendance 
+	(character_id, event_id, withdrawn, waitlisted, attended, updated_at) 
+	VALUES ($1, $2, false, $3, false, NOW()) 
+	ON CONFLICT (character_id, event_id) DO UPDATE SET withdrawn=false, waitlisted=EXCLUDED.waitlisted, updated_at=NOW();`,
+		r.CharacterId,
+		r.EventId,
+		r.Waitlisted,
+	)
+
+	return err
+}
+
+// SaveBatch signs characters up in one insert, characters that already have a row for the event such as one
+// saved by !attend or a waitlist promotion in the meantime are left as they are
 func (r *Attendance) SaveBatch(db *pgxpool.Pool, rows []Attendance) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
 		return err
@@ -74,18 +108,82 @@ func (r *Attendance) SaveBatch(db *pgxpool.Pool, rows []Attendance) error {
 
 	for _, r := range rows {
 		t := len(vals)
-		params = append(params, fmt.Sprintf("($%d, $%d, $%d, $%d)", t+1, t+2, t+3, t+4))
+		params = append(params, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", t+1, t+2, t+3, t+4, t+5))
 		vals = append(vals, r.CharacterId)
 		vals = append(vals, r.EventId)
 		vals = append(vals, r.Withdrawn)
+		vals = append(vals, r.Waitlisted)
 		vals = append(vals, now)
 	}
 
-	q := fmt.Sprintf("INSERT INTO attendance (character_id, event_id, withdrawn, updated_at) VALUES %s;", strings.Join(params, ","))
-	conn.QueryRow(context.Background(), q, vals...)
+	q := fmt.Sprintf(`INSERT INTO attendance (character_id, event_id, withdrawn, waitlisted, updated_at) VALUES %s 
+ON CONFLICT (character_id, event_id) DO NOTHING;`, strings.Join(params, ","))
+	_, err = conn.Exec(context.Background(), q, vals...)
 
-	return nil
+	return err
+}
+
+// RecordPresence marks exactly the given characters as having attended an event,
+// characters that were not signed up are added and signed up characters that were absent are marked as not attending
+func (r *Attendance) RecordPresence(db *pgxpool.Pool, eventId int64, characterIds []int64) error {
+	// a nil slice is sent as NULL which would leave attended NULL for everyone
+	if characterIds == nil {
+		characterIds = []int64{}
+	}
+
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
 
+	_, err = tx.Exec(ctx, `UPDATE attendance 
+SET attended = (character_id = ANY($2)), 
+withdrawn = withdrawn AND NOT (character_id = ANY($2)), 
+waitlisted = waitlisted AND NOT (character_id = ANY($2)), 
+updated_at = NOW() 
+WHERE event_id = $1;`, eventId, characterIds)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO attendance (character_id, event_id, attended, updated_at) 
+SELECT c, $1, true, NOW() FROM unnest($2::bigint[]) c 
+WHERE c NOT IN (SELECT character_id FROM attendance WHERE event_id = $1);`, eventId, characterIds)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+// MarkAttended adds the given characters to those that attended an event without changing anyone else
+func (r *Attendance) MarkAttended(db *pgxpool.Pool, eventId int64, characterIds []int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, `UPDATE attendance 
+SET attended = true, withdrawn = false, waitlisted = false, updated_at = NOW() 
+WHERE event_id = $1 AND character_id = ANY($2);`, eventId, characterIds)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.Exec(ctx, `INSERT INTO attendance (character_id, event_id, attended, updated_at) 
+SELECT c, $1, true, NOW() FROM unnest($2::bigint[]) c 
+WHERE c NOT IN (SELECT character_id FROM attendance WHERE event_id = $1);`, eventId, characterIds)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
 }
 
 func (r *Attendance) GetAttendees(db *pgxpool.Pool, eventId int64) ([]Character, error) {
@@ -96,13 +194,52 @@ func (r *Attendance) GetAttendees(db *pgxpool.Pool, eventId int64) ([]Character,
 
 	var attendees []Character
 	pgxscan.Select(context.Background(), db, &attendees, `SELECT * from characters where id IN (SELECT character_id FROM attendance 
-	WHERE event_id = $1 and withdrawn = false);`, eventId)
+	WHERE event_id = $1 and withdrawn = false and waitlisted = false);`, eventId)
+
+	defer conn.Release()
+
+	return attendees, nil
+}
+
+// GetAttended returns the characters that were checked in to an event
+func (r *Attendance) GetAttended(db *pgxpool.Pool, eventId int64) ([]Character, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
 
 	defer conn.Release()
 
+	var attendees []Character
+	q := `SELECT * from characters where id IN (SELECT character_id FROM attendance 
+	WHERE event_id = $1 and attended = true) order by name;`
+	if err = pgxscan.Select(context.Background(), db, &attendees, q, eventId); err != nil {
+		return nil, err
+	}
+
 	return attendees, nil
 }
 
+// GetWaitlist returns the waitlisted attendance of an event in the order it should be promoted
+func (r *Attendance) GetWaitlist(db *pgxpool.Pool, eventId int64) ([]Attendance, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var waitlist []Attendance
+	q := `SELECT * FROM attendance 
+WHERE event_id = $1 AND withdrawn = false AND waitlisted = true 
+order by id;`
+	if err = pgxscan.Select(context.Background(), db, &waitlist, q, eventId); err != nil {
+		return nil, err
+	}
+
+	return waitlist, nil
+}
+
 func (r *Attendance) GetMyAttendanceForEvent(db *pgxpool.Pool, eventId int64, userId string) ([]Attendance, error) {
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
@@ -112,7 +249,7 @@ func (r *Attendance) GetMyAttendanceForEvent(db *pgxpool.Pool, eventId int64, us
 	var attendees []Attendance
 	pgxscan.Select(context.Background(), db, &attendees, `SELECT a.* from attendance a
 LEFT JOIN characters c on a.character_id = c.id 
-WHERE c.created_by=$1
+WHERE c.created_by IN `+linkedOwners+`
 AND a.event_id=$2;`, userId, eventId)
 
 	defer conn.Release()
@@ -130,7 +267,7 @@ func (r *Attendance) GetPendingAttendance(db *pgxpool.Pool, userId string) ([]At
 	pgxscan.Select(context.Background(), db, &attendees, `SELECT a.* from attendance a
 LEFT JOIN characters c on a.character_id = c.id 
 LEFT JOIN events e on a.event_id = e.id
-WHERE c.created_by=$1
+WHERE c.created_by IN `+linkedOwners+`
 AND a.withdrawn=false
 AND e.event_time > NOW();`, userId)
 
@@ -155,7 +292,7 @@ func (r *Attendance) GetAttendeesForEvents(db *pgxpool.Pool, eventIds []int64) (
 	}
 
 	var attendees []Attendance
-	err = pgxscan.Select(context.Background(), db, &attendees, fmt.Sprintf(`SELECT * FROM attendance WHERE event_id IN (%s) AND withdrawn=false;`, strings.Join(idStrs, ", ")), ids...)
+	err = pgxscan.Select(context.Background(), db, &attendees, fmt.Sprintf(`SELECT * FROM attendance WHERE event_id IN (%s) AND withdrawn=false AND waitlisted=false;`, strings.Join(idStrs, ", ")), ids...)
 	if err != nil {
 		log.Print(err.Error())
 	}
@@ -190,3 +327,107 @@ func (r *Attendance) GetAttendeesForEvents(db *pgxpool.Pool, eventIds []int64) (
 	return res, nil
 
 }
+
+// PromoteWaitlist moves waitlisted characters into the event while there is room and lets their owners know
+func (r *Attendance) PromoteWaitlist(db *pgxpool.Pool, event Event, caps map[int64]int64, attending []Character) ([]Character, error) {
+	characterProvider := Character{}
+
+	waitlist, err := r.GetWaitlist(db, event.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(waitlist) == 0 {
+		return attending, nil
+	}
+
+	var charIds []int64
+	for _, w := range waitlist {
+		charIds = append(charIds, w.CharacterId)
+	}
+
+	toons, err := characterProvider.GetWhereIn(db, charIds)
+	if err != nil {
+		return nil, err
+	}
+
+	cMap := make(map[int64]Character)
+	for _, c := range toons {
+		cMap[c.Id] = c
+	}
+
+	for _, w := range waitlist {
+		c, ok := cMap[w.CharacterId]
+		if !ok || !event.Accepts(c) || !event.HasRoom(attending, caps, c) {
+			continue
+		}
+
+		w.Waitlisted = false
+		if err = w.Update(db); err != nil {
+			return nil, err
+		}
+		attending = append(attending, c)
+
+		n := Notification{
+			UserId: c.CreatedBy,
+			Message: fmt.Sprintf("A spot opened up in %s on %s, %s has been moved off the waitlist and is now attending.",
+				event.Title,
+				event.EventTime.Format(time.RFC822),
+				c.Name),
+		}
+		if err = n.Save(db); err != nil {
+			return nil, err
+		}
+
+		log.Printf("promoted %s from the waitlist of event %d", c.Name, event.Id)
+	}
+
+	return attending, nil
+}
+
+// attendanceChange is a sign up whose standing changed when the limits of its event were rechecked
+type attendanceChange struct {
+	row       Attendance
+	character Character
+	message   string
+}
+
+// recheckAttendance works out who still attends an event under its limits, current attendees keep their place ahead of the
+// waitlist and both go in sign up order. characters the event no longer accepts are withdrawn, the rest attend while there
+// is room and are waitlisted after that
+func recheckAttendance(event Event, caps map[int64]int64, rows []Attendance, toons map[int64]Character) []attendanceChange {
+	var (
+		attending []Character
+		changes   []attendanceChange
+	)
+
+	when := event.EventTime.Format(time.RFC822)
+
+	for _, row := range rows {
+		c, ok := toons[row.CharacterId]
+		if !ok {
+			continue
+		}
+
+		change := attendanceChange{row: row, character: c}
+		switch {
+		case !event.Accepts(c):
+			change.row.Withdrawn = true
+			change.row.Waitlisted = false
+			change.message = fmt.Sprintf("The limits of %s on %s changed and %s no longer qualifies, it has been withdrawn.", event.Title, when, c.Name)
+		case event.HasRoom(attending, caps, c):
+			attending = append(attending, c)
+			change.row.Waitlisted = false
+			change.message = fmt.Sprintf("A spot opened up in %s on %s, %s has been moved off the waitlist and is now attending.", event.Title, when, c.Name)
+		default:
+			change.row.Waitlisted = true
+			change.message = fmt.Sprintf("The limits of %s on %s changed and there is no longer room for %s, it has been moved to the waitlist.", event.Title, when, c.Name)
+		}
+
+		if change.row.Withdrawn != row.Withdrawn || change.row.Waitlisted != row.Waitlisted {
+			changes = append(changes, change)
+		}
+	}
+
+	return changes
+}
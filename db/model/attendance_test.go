@@ -0,0 +1,56 @@
+package model_test
+
+import (
+	"eqRaidBot/db/model"
+	"testing"
+)
+
+func TestRecheckAttendance(t *testing.T) {
+	event := model.Event{
+		MaxAttendees: 2,
+		MinLevel:     60,
+		AllowedTypes: []int64{model.TypeMain, model.TypeBox},
+	}
+
+	toons := map[int64]model.Character{
+		1: {Id: 1, Name: "Lowbie", Level: 55, CharacterType: model.TypeMain},
+		2: {Id: 2, Name: "Tank", Level: 60, Class: 1, CharacterType: model.TypeMain},
+		3: {Id: 3, Name: "Healer", Level: 60, Class: 13, CharacterType: model.TypeMain},
+		4: {Id: 4, Name: "Late", Level: 60, Class: 13, CharacterType: model.TypeMain},
+		5: {Id: 5, Name: "Waiting", Level: 60, Class: 2, CharacterType: model.TypeMain},
+	}
+
+	// attendees come first in sign up order followed by the waitlist
+	rows := []model.Attendance{
+		{Id: 10, CharacterId: 1},
+		{Id: 11, CharacterId: 2},
+		{Id: 12, CharacterId: 3},
+		{Id: 13, CharacterId: 4},
+		{Id: 14, CharacterId: 5, Waitlisted: true},
+	}
+
+	changed := model.RecheckAttendance(event, map[int64]int64{13: 1}, rows, toons)
+
+	expected := map[int64]model.Attendance{
+		10: {Id: 10, CharacterId: 1, Withdrawn: true},
+		13: {Id: 13, CharacterId: 4, Waitlisted: true},
+	}
+
+	if len(changed) != len(expected) {
+		t.Fatalf("expected %d changes, got %+v", len(expected), changed)
+	}
+
+	for _, c := range changed {
+		e, ok := expected[c.Id]
+		if !ok || c.Withdrawn != e.Withdrawn || c.Waitlisted != e.Waitlisted {
+			t.Errorf("unexpected change %+v", c)
+		}
+	}
+
+	// lifting the cap lets the waitlist in
+	event.MaxAttendees = 0
+	changed = model.RecheckAttendance(event, nil, rows[1:], toons)
+	if len(changed) != 1 || changed[0].Id != 14 || changed[0].Waitlisted {
+		t.Errorf("expected Waiting to come off the waitlist, got %+v", changed)
+	}
+}
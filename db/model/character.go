@@ -2,11 +2,13 @@ package model
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
 
 	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
@@ -22,17 +24,66 @@ var CharTypeMap = map[int64]string{
 	TypeAlt:  "Alt",
 }
 
+// CharTypeByName looks up a character type from its name, ignoring case
+func CharTypeByName(s string) (int64, bool) {
+	for id, name := range CharTypeMap {
+		if strings.EqualFold(name, s) {
+			return id, true
+		}
+	}
+	return 0, false
+}
+
+var (
+	// ErrNameTaken is returned when saving a character whose name is already registered
+	ErrNameTaken = errors.New("character name is already taken")
+	// ErrMainTaken is returned when saving a second main for the same owner
+	ErrMainTaken = errors.New("owner already has a main")
+	// ErrBoxLimit is returned when saving more boxes for an owner than the guild allows
+	ErrBoxLimit = errors.New("owner has no free box slots")
+)
+
+const uniqueViolation = "23505"
+
+var constraintErrors = map[string]error{
+	"characters_name_uniq_idx": ErrNameTaken,
+	"characters_one_main_idx":  ErrMainTaken,
+	"characters_box_slot_idx":  ErrBoxLimit,
+}
+
+// characterError turns constraint violations into errors the commands can explain to users
+func characterError(err error) error {
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+		if e, ok := constraintErrors[pgErr.ConstraintName]; ok {
+			return e
+		}
+	}
+	return err
+}
+
 type Character struct {
 	Id            int64
 	Name          string
 	Class         int64
+	Race          int64
 	Level         int64
 	AA            int64
 	CharacterType int64
 	CreatedBy     string
+	Retired       bool
+	Rank          string
+	Notes         string
+	LastOn        *time.Time
+	BoxSlot       *int64 // assigned by the database so owners stay within the guilds box limit
 	CreatedAt     time.Time
 }
 
+// IsClaimed reports whether a discord user owns the character, imported characters start unclaimed
+func (r *Character) IsClaimed() bool {
+	return r.CreatedBy != ""
+}
+
 func (r *Character) Save(db *pgxpool.Pool) error {
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
@@ -43,22 +94,219 @@ func (r *Character) Save(db *pgxpool.Pool) error {
 
 	var row idRow
 
-	conn.QueryRow(context.Background(), `INSERT INTO characters 
-	(name, class, level, aa, character_type, created_by) 
-	VALUES ($1, $2, $3, $4, $5, $6) RETURNING id;`,
+	err = conn.QueryRow(context.Background(), `INSERT INTO characters 
+	(name, class, race, level, aa, character_type, created_by, rank, notes, last_on) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;`,
 		r.Name,
 		r.Class,
+		r.Race,
 		r.Level,
 		r.AA,
 		r.CharacterType,
 		r.CreatedBy,
-	).Scan(&row)
+		r.Rank,
+		r.Notes,
+		r.LastOn,
+	).Scan(&row.Id)
+	if err != nil {
+		return characterError(err)
+	}
 
 	r.Id = row.Id
 
 	return nil
 }
 
+func (r *Character) Update(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE characters 
+SET name=$1, class=$2, race=$3, level=$4, aa=$5, character_type=$6, rank=$7, notes=$8, last_on=$9 
+WHERE id=$10;`,
+		r.Name,
+		r.Class,
+		r.Race,
+		r.Level,
+		r.AA,
+		r.CharacterType,
+		r.Rank,
+		r.Notes,
+		r.LastOn,
+		r.Id,
+	)
+
+	return characterError(err)
+}
+
+// Import saves new characters and updates existing ones in a single transaction
+func (r *Character) Import(db *pgxpool.Pool, creates []Character, updates []Character) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	for _, c := range creates {
+		_, err = tx.Exec(ctx, `INSERT INTO characters 
+	(name, class, level, aa, character_type, created_by, rank, notes, last_on) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9);`,
+			c.Name,
+			c.Class,
+			c.Level,
+			c.AA,
+			c.CharacterType,
+			c.CreatedBy,
+			c.Rank,
+			c.Notes,
+			c.LastOn,
+		)
+		if err != nil {
+			return characterError(err)
+		}
+	}
+
+	for _, c := range updates {
+		_, err = tx.Exec(ctx, `UPDATE characters SET class=$1, level=$2, rank=$3, notes=$4, last_on=$5 WHERE id=$6;`,
+			c.Class,
+			c.Level,
+			c.Rank,
+			c.Notes,
+			c.LastOn,
+			c.Id,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// RaiseLevels sets the level of each character to the one given when it is higher, levels seen in logs never lower a character
+func (r *Character) RaiseLevels(db *pgxpool.Pool, levels map[int64]int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	for id, level := range levels {
+		if _, err = tx.Exec(ctx, `UPDATE characters SET level=$1 WHERE id=$2 AND level < $1;`, level, id); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// Retire soft deletes a character and withdraws it from any event that has not happened yet
+func (r *Character) Retire(db *pgxpool.Pool) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `UPDATE characters SET retired=true WHERE id=$1;`, r.Id); err != nil {
+		return characterError(err)
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE attendance SET withdrawn=true, updated_at=NOW() 
+WHERE character_id=$1 
+AND event_id IN (SELECT id FROM events WHERE event_time > NOW());`, r.Id)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.Retired = true
+
+	return nil
+}
+
+// PromoteToMain makes the character the main of its account, the accounts previous main takes over the characters old type.
+// mains are kept per discord account like the one main index, a main on a linked account is left alone
+func (r *Character) PromoteToMain(db *pgxpool.Pool) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	var toons []Character
+	if err = pgxscan.Select(ctx, tx, &toons, `SELECT * FROM characters WHERE created_by=$1 AND retired=false FOR UPDATE;`, r.CreatedBy); err != nil {
+		return err
+	}
+
+	// the character steps down to an alt first so the previous main can take over its box slot
+	if _, err = tx.Exec(ctx, `UPDATE characters SET character_type=$1 WHERE id=$2;`, TypeAlt, r.Id); err != nil {
+		return characterError(err)
+	}
+
+	if main, ok := previousMain(toons, *r); ok {
+		if _, err = tx.Exec(ctx, `UPDATE characters SET character_type=$1 WHERE id=$2;`, r.CharacterType, main.Id); err != nil {
+			return characterError(err)
+		}
+	}
+
+	if _, err = tx.Exec(ctx, `UPDATE characters SET character_type=$1 WHERE id=$2;`, TypeMain, r.Id); err != nil {
+		return characterError(err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.CharacterType = TypeMain
+
+	return nil
+}
+
+// previousMain finds the main that steps down when promoted becomes the main of its account
+func previousMain(toons []Character, promoted Character) (Character, bool) {
+	for _, t := range toons {
+		if t.Id != promoted.Id && t.CreatedBy == promoted.CreatedBy && t.CharacterType == TypeMain && !t.Retired {
+			return t, true
+		}
+	}
+	return Character{}, false
+}
+
+// GetByAccount returns the active characters registered by exactly this discord account, linked accounts are left out
+// because the main and box limits in the database are kept per account
+func (r *Character) GetByAccount(db *pgxpool.Pool, userId string) ([]Character, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var toons []Character
+	q := `SELECT * FROM characters WHERE created_by = $1 AND retired = false order by level desc;`
+	if err = pgxscan.Select(context.Background(), db, &toons, q, userId); err != nil {
+		return nil, err
+	}
+
+	return toons, nil
+}
+
 func (r *Character) GetByOwner(db *pgxpool.Pool, userId string) ([]Character, error) {
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
@@ -67,9 +315,10 @@ func (r *Character) GetByOwner(db *pgxpool.Pool, userId string) ([]Character, er
 
 	defer conn.Release()
 
+	// characters registered on any linked account belong to the same player
 	var toons []Character
 	q := `SELECT * FROM characters 
-	WHERE created_by = $1 order by level desc;`
+	WHERE created_by IN ` + linkedOwners + ` AND retired = false order by level desc;`
 	if err = pgxscan.Select(context.Background(), db, &toons, q, userId); err != nil {
 		return nil, err
 	}
@@ -77,6 +326,43 @@ func (r *Character) GetByOwner(db *pgxpool.Pool, userId string) ([]Character, er
 	return toons, nil
 }
 
+// GetAll returns every character including retired and unclaimed ones
+func (r *Character) GetAll(db *pgxpool.Pool) ([]Character, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Release()
+
+	var toons []Character
+	if err = pgxscan.Select(context.Background(), db, &toons, `SELECT * FROM characters order by name;`); err != nil {
+		return nil, err
+	}
+
+	return toons, nil
+}
+
+// GetByName finds a character by name ignoring case, ok is false when there is no such character
+func (r *Character) GetByName(db *pgxpool.Pool, name string) (Character, bool, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return Character{}, false, err
+	}
+	defer conn.Release()
+
+	var toons []Character
+	q := `SELECT * FROM characters WHERE lower(name) = lower($1) order by retired, id limit 1;`
+	if err = pgxscan.Select(context.Background(), db, &toons, q, name); err != nil {
+		return Character{}, false, err
+	}
+
+	if len(toons) == 0 {
+		return Character{}, false, nil
+	}
+
+	return toons[0], true, nil
+}
+
 func (r *Character) GetAllActive(db *pgxpool.Pool) ([]Character, error) {
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
@@ -85,8 +371,7 @@ func (r *Character) GetAllActive(db *pgxpool.Pool) ([]Character, error) {
 	defer conn.Release()
 
 	var toons []Character
-	// types main and box
-	q := `SELECT * FROM characters where character_type IN(1,2) order by level desc;`
+	q := `SELECT * FROM characters where retired = false and created_by <> '' order by level desc;`
 	if err = pgxscan.Select(context.Background(), db, &toons, q); err != nil {
 		return nil, err
 	}
@@ -102,9 +387,11 @@ func (r *Character) GetAllNotAttendingEvent(db *pgxpool.Pool, eventId int64) ([]
 	defer conn.Release()
 
 	var toons []Character
-	// types main and box
+	// the events attendance policy decides which types are taken
 	q := `SELECT * FROM characters 
-where character_type IN(1,2) 
+where character_type = ANY((select allowed_types from events where id = $1)) 
+and retired = false 
+and created_by <> '' 
 and id NOT IN (select character_id from attendance where event_id = $1)
 order by level desc;`
 	if err = pgxscan.Select(context.Background(), db, &toons, q, eventId); err != nil {
@@ -122,10 +409,10 @@ func (r *Character) GetAllAttendingEvent(db *pgxpool.Pool, eventId int64) ([]Cha
 	defer conn.Release()
 
 	var toons []Character
-	// types main and box
+	// characters signed up before the events policy changed are left off
 	q := `SELECT * FROM characters 
-where character_type IN(1,2) 
-and id IN (select character_id from attendance where event_id = $1)
+where character_type = ANY((select allowed_types from events where id = $1)) 
+and id IN (select character_id from attendance where event_id = $1 and withdrawn = false and waitlisted = false)
 order by level desc;`
 	if err = pgxscan.Select(context.Background(), db, &toons, q, eventId); err != nil {
 		return nil, err
@@ -0,0 +1,165 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ErrNoEpicSteps is returned when removing a step from a class epic that has none
+var ErrNoEpicSteps = errors.New("the epic has no steps")
+
+// EpicStep is one step of a class epic in the guilds catalog, position orders the steps within a class
+type EpicStep struct {
+	Id        int64
+	Class     int64
+	Position  int64
+	Name      string
+	CreatedBy string
+	CreatedAt time.Time
+}
+
+// CharacterEpic is how many catalog steps of its class epic a character has completed and whether the weapon is done
+type CharacterEpic struct {
+	CharacterId int64
+	Step        int64
+	Finished    bool
+	UpdatedAt   time.Time
+}
+
+// EpicProgressResult is a character with the number of epic steps it has completed
+type EpicProgressResult struct {
+	Character
+	Step     int64
+	Finished bool
+}
+
+// Save adds the step to the end of its class epic
+func (r *EpicStep) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	return conn.QueryRow(context.Background(), `INSERT INTO epic_steps (class, position, name, created_by) 
+VALUES ($1, (SELECT COALESCE(MAX(position), 0) + 1 FROM epic_steps WHERE class = $1), $2, $3) RETURNING id, position;`,
+		r.Class,
+		r.Name,
+		r.CreatedBy,
+	).Scan(&r.Id, &r.Position)
+}
+
+// DeleteLast removes the last step of a class epic, characters past the new end are pulled back to it
+func (r *EpicStep) DeleteLast(db *pgxpool.Pool, class int64) error {
+	tx, err := db.Begin(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(context.Background())
+
+	var position int64
+	err = tx.QueryRow(context.Background(), `DELETE FROM epic_steps WHERE class = $1 AND position = (SELECT MAX(position) FROM epic_steps WHERE class = $1) 
+RETURNING position;`, class).Scan(&position)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return ErrNoEpicSteps
+		}
+		return err
+	}
+
+	_, err = tx.Exec(context.Background(), `UPDATE character_epics ce SET step = $1, updated_at = CURRENT_TIMESTAMP FROM characters c 
+WHERE c.id = ce.character_id AND c.class = $2 AND ce.step > $1;`, position-1, class)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit(context.Background())
+}
+
+// GetCatalog returns the step names of every cataloged class epic in order
+func (r *EpicStep) GetCatalog(db *pgxpool.Pool) (map[int64][]string, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []EpicStep
+	if err = pgxscan.Select(context.Background(), db, &rows, `SELECT * FROM epic_steps order by class, position;`); err != nil {
+		return nil, err
+	}
+
+	catalog := make(map[int64][]string)
+	for _, v := range rows {
+		catalog[v.Class] = append(catalog[v.Class], v.Name)
+	}
+
+	return catalog, nil
+}
+
+func (r *CharacterEpic) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `INSERT INTO character_epics (character_id, step, finished) VALUES ($1, $2, $3) 
+ON CONFLICT (character_id) DO UPDATE SET step = EXCLUDED.step, finished = EXCLUDED.finished, updated_at = CURRENT_TIMESTAMP;`, r.CharacterId, r.Step, r.Finished)
+
+	return err
+}
+
+// GetForCharacters returns the epic progress of each character, characters that never started are left out
+func (r *CharacterEpic) GetForCharacters(db *pgxpool.Pool, characterIds []int64) (map[int64]CharacterEpic, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []CharacterEpic
+	q := `SELECT * FROM character_epics WHERE character_id = ANY($1);`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, characterIds); err != nil {
+		return nil, err
+	}
+
+	progress := make(map[int64]CharacterEpic)
+	for _, v := range rows {
+		progress[v.CharacterId] = v
+	}
+
+	return progress, nil
+}
+
+// GetProgress returns every active main and any other active character that has started its epic, furthest along first
+func (r *CharacterEpic) GetProgress(db *pgxpool.Pool) ([]EpicProgressResult, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	q := `SELECT c.*, COALESCE(ce.step, 0) AS step, COALESCE(ce.finished, false) AS finished FROM characters c 
+LEFT JOIN character_epics ce ON ce.character_id = c.id 
+WHERE c.retired = false AND c.created_by <> '' AND (c.character_type = $1 OR ce.step > 0 OR ce.finished) 
+order by c.class, finished desc, step desc, c.name;`
+
+	var results []EpicProgressResult
+	if err = pgxscan.Select(context.Background(), db, &results, q, TypeMain); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// InventoryItem is an item a character held in its last inventory upload
+type InventoryItem struct {
+	CharacterId int64
+	Location    string
+	Name        string
+	ItemId      int64
+	Count       int64
+	Slots       int64
+	ImportedAt  time.Time
+}
+
+// ItemHolder is an inventory item with the character holding it
+type ItemHolder struct {
+	InventoryItem
+	CharacterName string
+	Class         int64
+	CreatedBy     string
+}
+
+// ReplaceForCharacter swaps the stored inventory of a character for a new upload
+func (r *InventoryItem) ReplaceForCharacter(db *pgxpool.Pool, characterId int64, items []InventoryItem) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `DELETE FROM character_inventory WHERE character_id = $1;`, characterId); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		_, err = tx.Exec(ctx, `INSERT INTO character_inventory (character_id, location, name, item_id, count, slots) 
+VALUES ($1, $2, $3, $4, $5, $6);`,
+			characterId,
+			item.Location,
+			item.Name,
+			item.ItemId,
+			item.Count,
+			item.Slots,
+		)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// FindHolders returns the items of active characters whose name contains the search, ignoring case
+func (r *InventoryItem) FindHolders(db *pgxpool.Pool, search string, limit int) ([]ItemHolder, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	q := `SELECT i.*, c.name AS character_name, c.class, c.created_by FROM character_inventory i 
+JOIN characters c ON c.id = i.character_id 
+WHERE strpos(lower(i.name), lower($1)) > 0 AND c.retired = false 
+order by i.name, c.name, i.location limit $2;`
+
+	var holders []ItemHolder
+	if err = pgxscan.Select(context.Background(), db, &holders, q, search, limit); err != nil {
+		return nil, err
+	}
+
+	return holders, nil
+}
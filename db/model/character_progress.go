@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// CharacterProgress is the level and AA of a character from the moment either changed
+type CharacterProgress struct {
+	Id          int64
+	CharacterId int64
+	Level       int64
+	AA          int64
+	RecordedAt  time.Time
+}
+
+// ProgressMover is a character with how far it moved over a period
+type ProgressMover struct {
+	Character
+	LevelGain int64
+	AAGain    int64
+}
+
+// GetForCharacter returns the history of a character oldest first
+func (r *CharacterProgress) GetForCharacter(db *pgxpool.Pool, characterId int64) ([]CharacterProgress, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []CharacterProgress
+	q := `SELECT * FROM character_progress WHERE character_id = $1 order by recorded_at, id;`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, characterId); err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+// GetMovers compares active characters today with where they were at since, characters registered later
+// are compared with where they started. Characters that did not move are left out
+func (r *CharacterProgress) GetMovers(db *pgxpool.Pool, since time.Time, limit int) ([]ProgressMover, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	q := `SELECT c.*, c.level - COALESCE(b.level, f.level) AS level_gain, c.aa - COALESCE(b.aa, f.aa) AS aa_gain 
+FROM characters c 
+LEFT JOIN LATERAL (SELECT level, aa FROM character_progress 
+	WHERE character_id = c.id AND recorded_at <= $1 order by recorded_at desc, id desc limit 1) b ON true 
+LEFT JOIN LATERAL (SELECT level, aa FROM character_progress 
+	WHERE character_id = c.id order by recorded_at, id limit 1) f ON true 
+WHERE c.retired = false AND c.created_by <> '' 
+	AND (c.level > COALESCE(b.level, f.level) OR c.aa > COALESCE(b.aa, f.aa)) 
+order by level_gain desc, aa_gain desc, c.name limit $2;`
+
+	var movers []ProgressMover
+	if err = pgxscan.Select(context.Background(), db, &movers, q, since, limit); err != nil {
+		return nil, err
+	}
+
+	return movers, nil
+}
+
+// GetStalled returns active mains below maxLevel whose level and AA have not changed since the given time
+func (r *CharacterProgress) GetStalled(db *pgxpool.Pool, since time.Time, maxLevel int64) ([]Character, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	q := `SELECT c.* FROM characters c 
+WHERE c.retired = false AND c.created_by <> '' AND c.character_type = $1 AND c.level < $2 
+	AND NOT EXISTS (SELECT 1 FROM character_progress p WHERE p.character_id = c.id AND p.recorded_at > $3) 
+order by c.level desc, c.name;`
+
+	var toons []Character
+	if err = pgxscan.Select(context.Background(), db, &toons, q, TypeMain, maxLevel, since); err != nil {
+		return nil, err
+	}
+
+	return toons, nil
+}
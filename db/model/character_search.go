@@ -0,0 +1,101 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// CharacterFilter narrows a character search, zero values and nil maximums are ignored
+type CharacterFilter struct {
+	Classes       []int64
+	Races         []int64
+	Types         []int64
+	MinLevel      int64
+	MaxLevel      *int64
+	MinAA         int64
+	MaxAA         *int64
+	AttendedSince time.Time
+	Limit         int
+	Offset        int
+}
+
+// CharacterSearchResult is a character with the time of the last event it attended
+type CharacterSearchResult struct {
+	Character
+	LastAttended *time.Time
+}
+
+// Find searches active characters, results are ordered by level and name
+func (r *Character) Find(db *pgxpool.Pool, filter CharacterFilter) ([]CharacterSearchResult, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var (
+		where = []string{"c.retired = false"}
+		vals  []interface{}
+	)
+
+	if len(filter.Classes) > 0 {
+		vals = append(vals, filter.Classes)
+		where = append(where, fmt.Sprintf("c.class = ANY($%d)", len(vals)))
+	}
+
+	if len(filter.Races) > 0 {
+		vals = append(vals, filter.Races)
+		where = append(where, fmt.Sprintf("c.race = ANY($%d)", len(vals)))
+	}
+
+	if len(filter.Types) > 0 {
+		vals = append(vals, filter.Types)
+		where = append(where, fmt.Sprintf("c.character_type = ANY($%d)", len(vals)))
+	}
+
+	if filter.MinLevel > 0 {
+		vals = append(vals, filter.MinLevel)
+		where = append(where, fmt.Sprintf("c.level >= $%d", len(vals)))
+	}
+
+	if filter.MaxLevel != nil {
+		vals = append(vals, *filter.MaxLevel)
+		where = append(where, fmt.Sprintf("c.level <= $%d", len(vals)))
+	}
+
+	if filter.MinAA > 0 {
+		vals = append(vals, filter.MinAA)
+		where = append(where, fmt.Sprintf("c.aa >= $%d", len(vals)))
+	}
+
+	if filter.MaxAA != nil {
+		vals = append(vals, *filter.MaxAA)
+		where = append(where, fmt.Sprintf("c.aa <= $%d", len(vals)))
+	}
+
+	if !filter.AttendedSince.IsZero() {
+		vals = append(vals, filter.AttendedSince)
+		where = append(where, fmt.Sprintf("la.last_attended >= $%d", len(vals)))
+	}
+
+	vals = append(vals, filter.Limit, filter.Offset)
+	q := fmt.Sprintf(`SELECT c.*, la.last_attended FROM characters c 
+LEFT JOIN (SELECT a.character_id, MAX(e.event_time) AS last_attended FROM attendance a 
+	JOIN events e ON e.id = a.event_id 
+	WHERE a.attended = true GROUP BY a.character_id) la ON la.character_id = c.id 
+WHERE %s 
+order by c.level desc, c.name limit $%d offset $%d;`, strings.Join(where, " AND "), len(vals)-1, len(vals))
+
+	var results []CharacterSearchResult
+	if err = pgxscan.Select(context.Background(), db, &results, q, vals...); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
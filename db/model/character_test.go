@@ -0,0 +1,24 @@
+package model_test
+
+import (
+	"eqRaidBot/db/model"
+	"testing"
+)
+
+func TestPreviousMainStaysOnTheAccount(t *testing.T) {
+	toons := []model.Character{
+		{Id: 1, Name: "Linkedmain", CreatedBy: "linked", CharacterType: model.TypeMain},
+		{Id: 2, Name: "Oldmain", CreatedBy: "owner", CharacterType: model.TypeMain},
+		{Id: 3, Name: "Newmain", CreatedBy: "owner", CharacterType: model.TypeBox},
+	}
+
+	main, ok := model.PreviousMain(toons, toons[2])
+	if !ok || main.Id != 2 {
+		t.Errorf("expected Oldmain to step down, got %s", main.Name)
+	}
+
+	// the main of a linked account is never demoted, limits are kept per account
+	if main, ok = model.PreviousMain(toons[:1], toons[2]); ok {
+		t.Errorf("expected no main to step down, got %s", main.Name)
+	}
+}
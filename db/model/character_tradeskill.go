@@ -0,0 +1,93 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// CharacterTradeskill is the last skill value a member reported for one of their characters
+type CharacterTradeskill struct {
+	CharacterId int64
+	Skill       int64
+	Value       int64
+	UpdatedAt   time.Time
+}
+
+// CrafterResult is a character with its value in the tradeskill searched for
+type CrafterResult struct {
+	Character
+	Value int64
+}
+
+// SetForCharacter records the given tradeskills, skills left out keep their previous value
+func (r *CharacterTradeskill) SetForCharacter(db *pgxpool.Pool, characterId int64, skills map[int64]int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	for skill, value := range skills {
+		_, err = tx.Exec(ctx, `INSERT INTO character_tradeskills (character_id, skill, value) VALUES ($1, $2, $3) 
+ON CONFLICT (character_id, skill) DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP;`,
+			characterId, skill, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetForCharacters returns the recorded tradeskills of each character keyed by character and then skill
+func (r *CharacterTradeskill) GetForCharacters(db *pgxpool.Pool, characterIds []int64) (map[int64]map[int64]int64, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []CharacterTradeskill
+	q := `SELECT * FROM character_tradeskills WHERE character_id = ANY($1);`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, characterIds); err != nil {
+		return nil, err
+	}
+
+	skills := make(map[int64]map[int64]int64)
+	for _, v := range rows {
+		if _, ok := skills[v.CharacterId]; !ok {
+			skills[v.CharacterId] = make(map[int64]int64)
+		}
+		skills[v.CharacterId][v.Skill] = v.Value
+	}
+
+	return skills, nil
+}
+
+// GetCrafters returns active claimed characters with at least min in the skill, highest first
+func (r *CharacterTradeskill) GetCrafters(db *pgxpool.Pool, skill int64, min int64, limit int) ([]CrafterResult, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	q := `SELECT c.*, ct.value FROM characters c 
+JOIN character_tradeskills ct ON ct.character_id = c.id 
+WHERE ct.skill = $1 AND ct.value > 0 AND ct.value >= $2 AND c.retired = false AND c.created_by <> '' 
+order by ct.value desc, c.name limit $3;`
+
+	var results []CrafterResult
+	if err = pgxscan.Select(context.Background(), db, &results, q, skill, min, limit); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
@@ -0,0 +1,159 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+const (
+	TransferStatusPending  = 1
+	TransferStatusApproved = 2
+	TransferStatusRejected = 3
+)
+
+// ErrTransferStale is returned when approving a transfer of a character that has changed hands since it was asked for,
+// the transfer is rejected so it leaves the review queue
+var ErrTransferStale = errors.New("character no longer belongs to the account that asked for the transfer")
+
+// CharacterTransfer is a request to move a character to another discord account, officers approve or reject it
+type CharacterTransfer struct {
+	Id          int64
+	CharacterId int64
+	FromUser    string
+	ToUser      string
+	Status      int64
+	DecidedBy   string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+func (r *CharacterTransfer) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	var row idRow
+
+	err = conn.QueryRow(context.Background(), `INSERT INTO character_transfers 
+	(character_id, from_user, to_user) 
+	VALUES ($1, $2, $3) RETURNING id;`,
+		r.CharacterId,
+		r.FromUser,
+		r.ToUser,
+	).Scan(&row.Id)
+	if err != nil {
+		return err
+	}
+
+	r.Id = row.Id
+
+	return nil
+}
+
+// Approve hands the character to the new owner as the given type, other pending transfers of the character are rejected
+func (r *CharacterTransfer) Approve(db *pgxpool.Pool, decidedBy string, charType int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	tag, err := tx.Exec(ctx, `UPDATE character_transfers SET status=$1, decided_by=$2, updated_at=NOW() 
+WHERE id=$3 AND status=$4;`, TransferStatusApproved, decidedBy, r.Id, TransferStatusPending)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("transfer has already been decided")
+	}
+
+	tag, err = tx.Exec(ctx, `UPDATE characters SET created_by=$1, character_type=$2 
+WHERE id=$3 AND created_by=$4;`, r.ToUser, charType, r.CharacterId, r.FromUser)
+	if err != nil {
+		return characterError(err)
+	}
+
+	if tag.RowsAffected() == 0 {
+		_, err = tx.Exec(ctx, `UPDATE character_transfers SET status=$1, decided_by=$2, updated_at=NOW() WHERE id=$3;`,
+			TransferStatusRejected, decidedBy, r.Id)
+		if err != nil {
+			return err
+		}
+
+		if err = tx.Commit(ctx); err != nil {
+			return err
+		}
+
+		r.Status = TransferStatusRejected
+		r.DecidedBy = decidedBy
+
+		return ErrTransferStale
+	}
+
+	// competing claims on the character can never be approved once it has moved
+	_, err = tx.Exec(ctx, `UPDATE character_transfers SET status=$1, decided_by=$2, updated_at=NOW() 
+WHERE character_id=$3 AND status=$4 AND id<>$5;`, TransferStatusRejected, decidedBy, r.CharacterId, TransferStatusPending, r.Id)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return err
+	}
+
+	r.Status = TransferStatusApproved
+	r.DecidedBy = decidedBy
+
+	return nil
+}
+
+func (r *CharacterTransfer) Reject(db *pgxpool.Pool, decidedBy string) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(), `UPDATE character_transfers SET status=$1, decided_by=$2, updated_at=NOW() 
+WHERE id=$3 AND status=$4;`, TransferStatusRejected, decidedBy, r.Id, TransferStatusPending)
+	if err != nil {
+		return err
+	}
+
+	if tag.RowsAffected() == 0 {
+		return errors.New("transfer has already been decided")
+	}
+
+	r.Status = TransferStatusRejected
+	r.DecidedBy = decidedBy
+
+	return nil
+}
+
+func (r *CharacterTransfer) GetPending(db *pgxpool.Pool) ([]CharacterTransfer, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var transfers []CharacterTransfer
+	q := `SELECT * FROM character_transfers WHERE status = $1 order by id;`
+	if err = pgxscan.Select(context.Background(), db, &transfers, q, TransferStatusPending); err != nil {
+		return nil, err
+	}
+
+	return transfers, nil
+}
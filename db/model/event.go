@@ -11,16 +11,154 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 )
 
+const (
+	EventStatusScheduled = 1
+	EventStatusStarted   = 2
+	EventStatusCompleted = 3
+	EventStatusCancelled = 4
+)
+
+var EventStatusMap = map[int64]string{
+	EventStatusScheduled: "Scheduled",
+	EventStatusStarted:   "Started",
+	EventStatusCompleted: "Completed",
+	EventStatusCancelled: "Cancelled",
+}
+
 type Event struct {
 	Id           int64
 	Title        string
 	Description  string
 	EventTime    time.Time
 	IsRepeatable bool
+	Status       int64
+	MaxAttendees int64
+	MinLevel     int64
+	AllowedTypes []int64
+	SplitCount   int64
+	Duration     int64 // minutes
 	CreatedBy    string
 	CreatedAt    time.Time
 }
 
+// DefaultDuration is used for events that do not say how long they last
+const DefaultDuration = 240
+
+// EndTime is when the event is expected to finish
+func (r *Event) EndTime() time.Time {
+	return r.EventTime.Add(time.Duration(r.Duration) * time.Minute)
+}
+
+// DefaultAllowedTypes are the character types that may attend an event unless it says otherwise
+var DefaultAllowedTypes = []int64{TypeBox, TypeMain}
+
+// AttendancePolicy is a named set of character types that may attend an event
+type AttendancePolicy struct {
+	Name  string
+	Types []int64
+}
+
+// AttendancePolicies are the policies an officer can choose from, the first is the default
+var AttendancePolicies = []AttendancePolicy{
+	{Name: "mains+boxes", Types: DefaultAllowedTypes},
+	{Name: "mains", Types: []int64{TypeMain}},
+	{Name: "alts", Types: []int64{TypeAlt}},
+	{Name: "any", Types: []int64{TypeBox, TypeMain, TypeAlt}},
+}
+
+// PolicyByName looks up the character types of a policy from its name, ignoring case
+func PolicyByName(s string) ([]int64, bool) {
+	for _, p := range AttendancePolicies {
+		if strings.EqualFold(p.Name, strings.TrimSpace(s)) {
+			return p.Types, true
+		}
+	}
+	return nil, false
+}
+
+// PolicyName names the policy matching a set of character types, sets that match no policy are listed by type
+func PolicyName(types []int64) string {
+	allowed := make(map[int64]bool)
+	for _, t := range types {
+		allowed[t] = true
+	}
+
+	for _, p := range AttendancePolicies {
+		if len(p.Types) != len(allowed) {
+			continue
+		}
+
+		match := true
+		for _, t := range p.Types {
+			if !allowed[t] {
+				match = false
+				break
+			}
+		}
+
+		if match {
+			return p.Name
+		}
+	}
+
+	var names []string
+	for _, t := range types {
+		names = append(names, CharTypeMap[t])
+	}
+
+	return strings.Join(names, "/")
+}
+
+// PolicyNames lists every policy name for prompts
+func PolicyNames() []string {
+	var names []string
+	for _, p := range AttendancePolicies {
+		names = append(names, p.Name)
+	}
+	return names
+}
+
+// Accepts reports whether a character meets the events level floor and type restrictions
+func (r *Event) Accepts(c Character) bool {
+	if c.Level < r.MinLevel {
+		return false
+	}
+
+	for _, t := range r.AllowedTypes {
+		if t == c.CharacterType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasRoom reports whether a character fits into the event given who is already attending and the per-class caps
+func (r *Event) HasRoom(attending []Character, caps map[int64]int64, c Character) bool {
+	if r.MaxAttendees > 0 && int64(len(attending)) >= r.MaxAttendees {
+		return false
+	}
+
+	limit, ok := caps[c.Class]
+	if !ok {
+		return true
+	}
+
+	var n int64
+	for _, v := range attending {
+		if v.Class == c.Class {
+			n++
+		}
+	}
+
+	return n < limit
+}
+
+// IsFinal reports whether the event has reached the end of its lifecycle
+func (r *Event) IsFinal() bool {
+	return r.Status == EventStatusCompleted || r.Status == EventStatusCancelled
+}
+
 type idRow struct {
 	Id int64
 }
@@ -35,21 +173,83 @@ func (r *Event) Save(db *pgxpool.Pool) error {
 
 	var row idRow
 
-	conn.QueryRow(context.Background(), `INSERT INTO events 
-	(title, description, event_time, is_repeatable, created_by) 
-	VALUES ($1, $2, $3, $4, $5) RETURNING id;`,
+	if len(r.AllowedTypes) == 0 {
+		r.AllowedTypes = DefaultAllowedTypes
+	}
+
+	if r.Duration <= 0 {
+		r.Duration = DefaultDuration
+	}
+
+	err = conn.QueryRow(context.Background(), `INSERT INTO events 
+	(title, description, event_time, is_repeatable, max_attendees, min_level, allowed_types, split_count, duration, created_by) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) RETURNING id;`,
 		r.Title,
 		r.Description,
 		r.EventTime,
 		r.IsRepeatable,
+		r.MaxAttendees,
+		r.MinLevel,
+		r.AllowedTypes,
+		r.SplitCount,
+		r.Duration,
 		r.CreatedBy,
-	).Scan(&row)
+	).Scan(&row.Id)
+	if err != nil {
+		return err
+	}
 
 	r.Id = row.Id
 
 	return nil
 }
 
+func (r *Event) Update(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE events 
+SET title=$1, description=$2, event_time=$3, duration=$4 
+WHERE id=$5;`,
+		r.Title,
+		r.Description,
+		r.EventTime,
+		r.Duration,
+		r.Id,
+	)
+
+	return err
+}
+
+// GetOverlapping returns the events that have not finished or been cancelled which overlap the given window
+func (r *Event) GetOverlapping(db *pgxpool.Pool, start, end time.Time, excludeId int64) ([]Event, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var events []Event
+	q := `SELECT * FROM events 
+	WHERE event_time < $1 
+	AND event_time + duration * interval '1 minute' > $2 
+	AND id <> $3 
+	AND status IN ($4, $5) 
+	order by event_time;`
+
+	err = pgxscan.Select(context.Background(), db, &events, q, end, start, excludeId, EventStatusScheduled, EventStatusStarted)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
 func (r *Event) GetAll(db *pgxpool.Pool) ([]Event, error) {
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
@@ -60,9 +260,9 @@ func (r *Event) GetAll(db *pgxpool.Pool) ([]Event, error) {
 
 	var events []Event
 	q := `SELECT * FROM events 
-	WHERE event_time > NOW() order by event_time;`
+	WHERE event_time > NOW() AND status IN ($1, $2) order by event_time;`
 
-	err = pgxscan.Select(context.Background(), db, &events, q)
+	err = pgxscan.Select(context.Background(), db, &events, q, EventStatusScheduled, EventStatusStarted)
 	if err != nil {
 		return nil, err
 	}
@@ -70,6 +270,216 @@ func (r *Event) GetAll(db *pgxpool.Pool) ([]Event, error) {
 	return events, nil
 }
 
+func (r *Event) GetActive(db *pgxpool.Pool) ([]Event, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var events []Event
+	q := `SELECT * FROM events 
+	WHERE status IN ($1, $2) order by event_time;`
+
+	err = pgxscan.Select(context.Background(), db, &events, q, EventStatusScheduled, EventStatusStarted)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *Event) GetWhereStatus(db *pgxpool.Pool, statuses []int64) ([]Event, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var events []Event
+	q := `SELECT * FROM events 
+	WHERE status = ANY($1) order by event_time;`
+
+	err = pgxscan.Select(context.Background(), db, &events, q, statuses)
+	if err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+func (r *Event) UpdateStatus(db *pgxpool.Pool, status int64) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE events SET status=$1 WHERE id=$2;`, status, r.Id)
+	if err != nil {
+		return err
+	}
+
+	r.Status = status
+
+	return nil
+}
+
+// SaveLimits replaces the limits, class caps, resists and flags of the event in one transaction and rechecks its sign ups
+// against them, owners of characters that were withdrawn or moved on or off the waitlist are notified
+func (r *Event) SaveLimits(db *pgxpool.Pool, caps map[int64]int64, resists map[int64]int64, flagIds []int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if len(r.AllowedTypes) == 0 {
+		r.AllowedTypes = DefaultAllowedTypes
+	}
+
+	_, err = tx.Exec(ctx, `UPDATE events 
+SET max_attendees=$1, min_level=$2, allowed_types=$3 
+WHERE id=$4;`,
+		r.MaxAttendees,
+		r.MinLevel,
+		r.AllowedTypes,
+		r.Id,
+	)
+	if err != nil {
+		return err
+	}
+
+	if err = replaceClassCaps(ctx, tx, r.Id, caps); err != nil {
+		return err
+	}
+
+	if err = replaceResists(ctx, tx, r.Id, resists); err != nil {
+		return err
+	}
+
+	if err = replaceFlags(ctx, tx, r.Id, flagIds); err != nil {
+		return err
+	}
+
+	var rows []Attendance
+	q := `SELECT * FROM attendance WHERE event_id = $1 AND withdrawn = false order by waitlisted, id FOR UPDATE;`
+	if err = pgxscan.Select(ctx, tx, &rows, q, r.Id); err != nil {
+		return err
+	}
+
+	var ids []int64
+	for _, a := range rows {
+		ids = append(ids, a.CharacterId)
+	}
+
+	var toons []Character
+	if err = pgxscan.Select(ctx, tx, &toons, `SELECT * FROM characters WHERE id = ANY($1);`, ids); err != nil {
+		return err
+	}
+
+	byId := make(map[int64]Character)
+	for _, c := range toons {
+		byId[c.Id] = c
+	}
+
+	for _, change := range recheckAttendance(*r, caps, rows, byId) {
+		_, err = tx.Exec(ctx, `UPDATE attendance SET withdrawn=$1, waitlisted=$2, updated_at=NOW() WHERE id=$3;`,
+			change.row.Withdrawn,
+			change.row.Waitlisted,
+			change.row.Id,
+		)
+		if err != nil {
+			return err
+		}
+
+		if change.character.CreatedBy == "" {
+			continue
+		}
+
+		if _, err = tx.Exec(ctx, `INSERT INTO notifications (user_id, message) VALUES ($1, $2);`, change.character.CreatedBy, change.message); err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// StopRepeating marks a repeating event as renewed so it is not renewed again
+func (r *Event) StopRepeating(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE events SET is_repeatable=false WHERE id=$1;`, r.Id)
+	if err != nil {
+		return err
+	}
+
+	r.IsRepeatable = false
+
+	return nil
+}
+
+// EventHistoryFilter narrows down the completed events returned by GetHistory, zero values are ignored
+type EventHistoryFilter struct {
+	From   time.Time
+	To     time.Time
+	Title  string
+	Limit  int
+	Offset int
+}
+
+// GetHistory pages through completed events, most recent first
+func (r *Event) GetHistory(db *pgxpool.Pool, filter EventHistoryFilter) ([]Event, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var (
+		where = []string{"status = $1"}
+		vals  = []interface{}{EventStatusCompleted}
+	)
+
+	if !filter.From.IsZero() {
+		vals = append(vals, filter.From)
+		where = append(where, fmt.Sprintf("event_time >= $%d", len(vals)))
+	}
+
+	if !filter.To.IsZero() {
+		vals = append(vals, filter.To)
+		where = append(where, fmt.Sprintf("event_time < $%d", len(vals)))
+	}
+
+	if filter.Title != "" {
+		vals = append(vals, filter.Title)
+		where = append(where, fmt.Sprintf("strpos(lower(title), lower($%d)) > 0", len(vals)))
+	}
+
+	vals = append(vals, filter.Limit, filter.Offset)
+	q := fmt.Sprintf(`SELECT * FROM events 
+	WHERE %s 
+	order by event_time desc limit $%d offset $%d;`, strings.Join(where, " AND "), len(vals)-1, len(vals))
+
+	var events []Event
+	if err = pgxscan.Select(context.Background(), db, &events, q, vals...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
 func (r *Event) GetAllNeedsRenewal(db *pgxpool.Pool) ([]Event, error) {
 	conn, err := db.Acquire(context.Background())
 	if err != nil {
@@ -100,7 +510,7 @@ func (r *Event) GetNext(db *pgxpool.Pool) (Event, error) {
 
 	var events []Event
 	pgxscan.Select(context.Background(), db, &events, `SELECT * FROM events 
-	WHERE event_time > NOW() order by event_time limit 1;`)
+	WHERE event_time > NOW() AND status IN ($1, $2) order by event_time limit 1;`, EventStatusScheduled, EventStatusStarted)
 
 	if len(events) > 0 {
 		return events[0], nil
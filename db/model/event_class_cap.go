@@ -0,0 +1,71 @@
+package model
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// EventClassCap limits how many characters of a single class may attend an event
+type EventClassCap struct {
+	EventId int64
+	Class   int64
+	Cap     int64
+}
+
+// GetForEvent returns the class caps of an event keyed by class
+func (r *EventClassCap) GetForEvent(db *pgxpool.Pool, eventId int64) (map[int64]int64, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []EventClassCap
+	q := `SELECT * FROM event_class_caps WHERE event_id = $1;`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, eventId); err != nil {
+		return nil, err
+	}
+
+	caps := make(map[int64]int64)
+	for _, v := range rows {
+		caps[v.Class] = v.Cap
+	}
+
+	return caps, nil
+}
+
+// ReplaceForEvent swaps all class caps of an event for the ones given
+func (r *EventClassCap) ReplaceForEvent(db *pgxpool.Pool, eventId int64, caps map[int64]int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = replaceClassCaps(ctx, tx, eventId, caps); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func replaceClassCaps(ctx context.Context, tx pgx.Tx, eventId int64, caps map[int64]int64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM event_class_caps WHERE event_id = $1;`, eventId); err != nil {
+		return err
+	}
+
+	for class, limit := range caps {
+		_, err := tx.Exec(ctx, `INSERT INTO event_class_caps (event_id, class, cap) VALUES ($1, $2, $3);`, eventId, class, limit)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
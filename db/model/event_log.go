@@ -0,0 +1,208 @@
+package model
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// EventKill is a mob killed during an event, read from an uploaded log
+type EventKill struct {
+	Id        int64
+	EventId   int64
+	Mob       string
+	KilledBy  string
+	KilledAt  time.Time
+	CreatedAt time.Time
+}
+
+// EventLoot is an item looted during an event, CharacterId is nil when the looter is not a known character
+type EventLoot struct {
+	Id            int64
+	EventId       int64
+	CharacterName string
+	CharacterId   *int64
+	Item          string
+	LootedAt      time.Time
+	CreatedAt     time.Time
+}
+
+// logMatchTolerance is how far apart two officers logs may put the same kill or loot, client clocks drift by a few seconds
+const logMatchTolerance = 10 * time.Second
+
+// logMatchMaxOffset bounds the whole hour offsets that are treated as a log read in another time zone
+const logMatchMaxOffset = 26 * time.Hour
+
+// SaveEventLog stores kills and loot for an event, kills and loot another log already recorded are skipped
+func SaveEventLog(db *pgxpool.Pool, kills []EventKill, loot []EventLoot) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	// log times are read in the zone of the players computer, they are stored in the bots zone like every other time
+	eventIds := make(map[int64]bool)
+	for i := range kills {
+		kills[i].KilledAt = kills[i].KilledAt.Local()
+		eventIds[kills[i].EventId] = true
+	}
+	for i := range loot {
+		loot[i].LootedAt = loot[i].LootedAt.Local()
+		eventIds[loot[i].EventId] = true
+	}
+
+	for eventId := range eventIds {
+		// two logs for the same event are imported one after the other so each sees what the other recorded
+		if _, err = tx.Exec(ctx, `SELECT id FROM events WHERE id = $1 FOR UPDATE;`, eventId); err != nil {
+			return err
+		}
+
+		var recordedKills []EventKill
+		if err = pgxscan.Select(ctx, tx, &recordedKills, `SELECT * FROM event_kills WHERE event_id = $1;`, eventId); err != nil {
+			return err
+		}
+
+		var recordedLoot []EventLoot
+		if err = pgxscan.Select(ctx, tx, &recordedLoot, `SELECT * FROM event_loot WHERE event_id = $1;`, eventId); err != nil {
+			return err
+		}
+
+		for _, k := range newKills(recordedKills, kills, eventId) {
+			_, err = tx.Exec(ctx, `INSERT INTO event_kills 
+	(event_id, mob, killed_by, killed_at) 
+	VALUES ($1, $2, $3, $4);`, k.EventId, k.Mob, k.KilledBy, k.KilledAt)
+			if err != nil {
+				return err
+			}
+		}
+
+		for _, l := range newLoot(recordedLoot, loot, eventId) {
+			_, err = tx.Exec(ctx, `INSERT INTO event_loot 
+	(event_id, character_name, character_id, item, looted_at) 
+	VALUES ($1, $2, $3, $4, $5);`, l.EventId, l.CharacterName, l.CharacterId, l.Item, l.LootedAt)
+			if err != nil {
+				return err
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// newKills leaves out the incoming kills of an event that another log already recorded, each recorded kill accounts for
+// one incoming kill so identical mobs killed moments apart are all kept
+func newKills(recorded []EventKill, incoming []EventKill, eventId int64) []EventKill {
+	used := make([]bool, len(recorded))
+
+	var fresh []EventKill
+	for _, k := range incoming {
+		if k.EventId != eventId {
+			continue
+		}
+
+		matched := false
+		for i, r := range recorded {
+			if used[i] || !strings.EqualFold(r.Mob, k.Mob) || !strings.EqualFold(r.KilledBy, k.KilledBy) || !sameLogMoment(r.KilledAt, k.KilledAt) {
+				continue
+			}
+			used[i] = true
+			matched = true
+			break
+		}
+
+		if !matched {
+			fresh = append(fresh, k)
+		}
+	}
+
+	return fresh
+}
+
+// newLoot leaves out the incoming loot of an event that another log already recorded, each recorded item accounts for
+// one incoming item so the same drop looted twice in a row is kept
+func newLoot(recorded []EventLoot, incoming []EventLoot, eventId int64) []EventLoot {
+	used := make([]bool, len(recorded))
+
+	var fresh []EventLoot
+	for _, l := range incoming {
+		if l.EventId != eventId {
+			continue
+		}
+
+		matched := false
+		for i, r := range recorded {
+			if used[i] || !strings.EqualFold(r.CharacterName, l.CharacterName) || !strings.EqualFold(r.Item, l.Item) || !sameLogMoment(r.LootedAt, l.LootedAt) {
+				continue
+			}
+			used[i] = true
+			matched = true
+			break
+		}
+
+		if !matched {
+			fresh = append(fresh, l)
+		}
+	}
+
+	return fresh
+}
+
+// sameLogMoment reports whether two log times can be the same moment seen by different clients, clocks drift by a few
+// seconds and a log read in the wrong time zone is off by whole hours
+func sameLogMoment(a, b time.Time) bool {
+	d := a.Sub(b)
+	if d < 0 {
+		d = -d
+	}
+
+	if d > logMatchMaxOffset+logMatchTolerance {
+		return false
+	}
+
+	off := d % time.Hour
+	if off > time.Hour/2 {
+		off = time.Hour - off
+	}
+
+	return off <= logMatchTolerance
+}
+
+func (r *EventKill) GetForEvent(db *pgxpool.Pool, eventId int64) ([]EventKill, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var kills []EventKill
+	q := `SELECT * FROM event_kills WHERE event_id = $1 order by killed_at;`
+	if err = pgxscan.Select(context.Background(), db, &kills, q, eventId); err != nil {
+		return nil, err
+	}
+
+	return kills, nil
+}
+
+func (r *EventLoot) GetForEvent(db *pgxpool.Pool, eventId int64) ([]EventLoot, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var loot []EventLoot
+	q := `SELECT * FROM event_loot WHERE event_id = $1 order by looted_at;`
+	if err = pgxscan.Select(context.Background(), db, &loot, q, eventId); err != nil {
+		return nil, err
+	}
+
+	return loot, nil
+}
@@ -0,0 +1,61 @@
+package model_test
+
+import (
+	"eqRaidBot/db/model"
+	"testing"
+	"time"
+)
+
+func TestNewKills(t *testing.T) {
+	at := time.Date(2026, 10, 19, 20, 30, 45, 0, time.UTC)
+
+	recorded := []model.EventKill{
+		{EventId: 1, Mob: "Lord Nagafen", KilledBy: "Boxalot", KilledAt: at},
+		{EventId: 1, Mob: "a lava drake", KilledBy: "Boxalot", KilledAt: at.Add(time.Minute)},
+	}
+
+	incoming := []model.EventKill{
+		// another officers clock is a second behind
+		{EventId: 1, Mob: "Lord Nagafen", KilledBy: "Boxalot", KilledAt: at.Add(-time.Second)},
+		// two drakes died in the same second, one of them is already recorded
+		{EventId: 1, Mob: "a lava drake", KilledBy: "Boxalot", KilledAt: at.Add(time.Minute)},
+		{EventId: 1, Mob: "a lava drake", KilledBy: "Boxalot", KilledAt: at.Add(time.Minute)},
+		// a later drake is a new kill
+		{EventId: 1, Mob: "a lava drake", KilledBy: "Boxalot", KilledAt: at.Add(10 * time.Minute)},
+	}
+
+	fresh := model.NewKills(recorded, incoming, 1)
+	if len(fresh) != 2 || fresh[0].Mob != "a lava drake" || fresh[1].KilledAt != at.Add(10*time.Minute) {
+		t.Errorf("expected the second drake and the later drake, got %+v", fresh)
+	}
+
+	// the same log read three hours off is all duplicates
+	var shifted []model.EventKill
+	for _, k := range recorded {
+		k.KilledAt = k.KilledAt.Add(3 * time.Hour)
+		shifted = append(shifted, k)
+	}
+
+	if fresh = model.NewKills(recorded, shifted, 1); len(fresh) != 0 {
+		t.Errorf("expected a log read in another time zone to match, got %+v", fresh)
+	}
+}
+
+func TestNewLoot(t *testing.T) {
+	at := time.Date(2026, 10, 19, 20, 31, 2, 0, time.UTC)
+
+	recorded := []model.EventLoot{
+		{EventId: 1, CharacterName: "Healsalot", Item: "Cloak of Flames", LootedAt: at},
+	}
+
+	incoming := []model.EventLoot{
+		{EventId: 1, CharacterName: "Healsalot", Item: "Cloak of Flames", LootedAt: at.Add(2 * time.Second)},
+		{EventId: 1, CharacterName: "Boxalot", Item: "Cloak of Flames", LootedAt: at.Add(2 * time.Second)},
+		{EventId: 2, CharacterName: "Boxalot", Item: "Orb of Exploration", LootedAt: at},
+	}
+
+	fresh := model.NewLoot(recorded, incoming, 1)
+	if len(fresh) != 1 || fresh[0].CharacterName != "Boxalot" {
+		t.Errorf("expected only the cloak Boxalot looted, got %+v", fresh)
+	}
+}
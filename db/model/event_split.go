@@ -0,0 +1,97 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// EventSplit places a character into a raid and group of a published split
+type EventSplit struct {
+	EventId     int64
+	RaidNumber  int64
+	GroupNumber int64
+	CharacterId int64
+	CreatedAt   time.Time
+}
+
+// ReplaceForEvent stores the split published for an event, replacing any earlier one
+func (r *EventSplit) ReplaceForEvent(db *pgxpool.Pool, eventId int64, splits [][][]Character) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `DELETE FROM event_splits WHERE event_id = $1;`, eventId); err != nil {
+		return err
+	}
+
+	for raid, groups := range splits {
+		for group, toons := range groups {
+			for _, c := range toons {
+				_, err = tx.Exec(ctx, `INSERT INTO event_splits 
+	(event_id, raid_number, group_number, character_id) 
+	VALUES ($1, $2, $3, $4);`, eventId, raid, group, c.Id)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetForEvent rebuilds the split saved for an event as raids of groups of characters
+func (r *EventSplit) GetForEvent(db *pgxpool.Pool, eventId int64) ([][][]Character, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []EventSplit
+	q := `SELECT * FROM event_splits WHERE event_id = $1 order by raid_number, group_number;`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, eventId); err != nil {
+		return nil, err
+	}
+
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	var charIds []int64
+	for _, v := range rows {
+		charIds = append(charIds, v.CharacterId)
+	}
+
+	c := Character{}
+	toons, err := c.GetWhereIn(db, charIds)
+	if err != nil {
+		return nil, err
+	}
+
+	cMap := make(map[int64]Character)
+	for _, t := range toons {
+		cMap[t.Id] = t
+	}
+
+	var splits [][][]Character
+	for _, v := range rows {
+		for int64(len(splits)) <= v.RaidNumber {
+			splits = append(splits, nil)
+		}
+		for int64(len(splits[v.RaidNumber])) <= v.GroupNumber {
+			splits[v.RaidNumber] = append(splits[v.RaidNumber], nil)
+		}
+		splits[v.RaidNumber][v.GroupNumber] = append(splits[v.RaidNumber][v.GroupNumber], cMap[v.CharacterId])
+	}
+
+	return splits, nil
+}
@@ -0,0 +1,88 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// EventTemplate holds the details of a commonly run event so it can be scheduled without retyping them
+type EventTemplate struct {
+	Id           int64
+	Name         string
+	Description  string
+	MinLevel     int64
+	SplitCount   int64
+	IsRepeatable bool
+	Duration     int64
+	AllowedTypes []int64
+	CreatedBy    string
+	CreatedAt    time.Time
+}
+
+func (r *EventTemplate) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	if len(r.AllowedTypes) == 0 {
+		r.AllowedTypes = DefaultAllowedTypes
+	}
+
+	var row idRow
+
+	err = conn.QueryRow(context.Background(), `INSERT INTO event_templates 
+	(name, description, min_level, split_count, is_repeatable, duration, allowed_types, created_by) 
+	VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id;`,
+		r.Name,
+		r.Description,
+		r.MinLevel,
+		r.SplitCount,
+		r.IsRepeatable,
+		r.Duration,
+		r.AllowedTypes,
+		r.CreatedBy,
+	).Scan(&row.Id)
+	if err != nil {
+		return err
+	}
+
+	r.Id = row.Id
+
+	return nil
+}
+
+func (r *EventTemplate) Delete(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `DELETE FROM event_templates WHERE id = $1;`, r.Id)
+
+	return err
+}
+
+func (r *EventTemplate) GetAll(db *pgxpool.Pool) ([]EventTemplate, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var templates []EventTemplate
+	q := `SELECT * FROM event_templates order by name;`
+	if err = pgxscan.Select(context.Background(), db, &templates, q); err != nil {
+		return nil, err
+	}
+
+	return templates, nil
+}
@@ -0,0 +1,20 @@
+package model
+
+// PreviousMain exposes how a promotion picks the main that steps down to the external tests
+var PreviousMain = previousMain
+
+// RecheckAttendance exposes how sign ups are rechecked against new event limits to the external tests,
+// only the rows that changed are returned
+func RecheckAttendance(event Event, caps map[int64]int64, rows []Attendance, toons map[int64]Character) []Attendance {
+	var changed []Attendance
+	for _, c := range recheckAttendance(event, caps, rows, toons) {
+		changed = append(changed, c.row)
+	}
+	return changed
+}
+
+// NewKills and NewLoot expose how logs from several officers are merged to the external tests
+var (
+	NewKills = newKills
+	NewLoot  = newLoot
+)
@@ -0,0 +1,299 @@
+package model
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgconn"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// ErrFlagExists is returned when saving a flag whose key is already in the catalog
+var ErrFlagExists = errors.New("flag already exists")
+
+// Flag is a key or zone access flag in the guilds catalog
+type Flag struct {
+	Id          int64
+	Key         string
+	Description string
+	CreatedBy   string
+	CreatedAt   time.Time
+}
+
+func (r *Flag) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	var row idRow
+
+	err = conn.QueryRow(context.Background(), `INSERT INTO flags (key, description, created_by) VALUES ($1, $2, $3) RETURNING id;`,
+		r.Key,
+		r.Description,
+		r.CreatedBy,
+	).Scan(&row.Id)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == uniqueViolation {
+			return ErrFlagExists
+		}
+		return err
+	}
+
+	r.Id = row.Id
+
+	return nil
+}
+
+func (r *Flag) GetAll(db *pgxpool.Pool) ([]Flag, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var flags []Flag
+	if err = pgxscan.Select(context.Background(), db, &flags, `SELECT * FROM flags order by key;`); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// GetByKeys returns the flags with the given keys, unknown keys are left out
+func (r *Flag) GetByKeys(db *pgxpool.Pool, keys []string) ([]Flag, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var flags []Flag
+	if err = pgxscan.Select(context.Background(), db, &flags, `SELECT * FROM flags WHERE key = ANY($1) order by key;`, keys); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// CharacterFlag is a flag a member reported for one of their characters
+type CharacterFlag struct {
+	CharacterId int64
+	FlagId      int64
+	Verified    bool
+	VerifiedBy  string
+	ReportedAt  time.Time
+	VerifiedAt  *time.Time
+}
+
+// Report records the flag unverified, it returns false when the character already has the flag recorded
+func (r *CharacterFlag) Report(db *pgxpool.Pool) (bool, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return false, err
+	}
+
+	defer conn.Release()
+
+	tag, err := conn.Exec(context.Background(), `INSERT INTO character_flags (character_id, flag_id) VALUES ($1, $2) 
+ON CONFLICT (character_id, flag_id) DO NOTHING;`, r.CharacterId, r.FlagId)
+	if err != nil {
+		return false, err
+	}
+
+	return tag.RowsAffected() == 1, nil
+}
+
+func (r *CharacterFlag) Verify(db *pgxpool.Pool, verifiedBy string) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE character_flags SET verified=true, verified_by=$1, verified_at=CURRENT_TIMESTAMP 
+WHERE character_id=$2 AND flag_id=$3;`, verifiedBy, r.CharacterId, r.FlagId)
+
+	return err
+}
+
+// Reject removes a report so the member can report the flag again once they have it
+func (r *CharacterFlag) Reject(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `DELETE FROM character_flags WHERE character_id=$1 AND flag_id=$2 AND verified=false;`, r.CharacterId, r.FlagId)
+
+	return err
+}
+
+// GetUnverified returns the reports waiting for an officer, oldest first
+func (r *CharacterFlag) GetUnverified(db *pgxpool.Pool) ([]CharacterFlag, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var flags []CharacterFlag
+	if err = pgxscan.Select(context.Background(), db, &flags, `SELECT * FROM character_flags WHERE verified = false order by reported_at;`); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// GetForCharacters returns the recorded flags of each character keyed by character and then flag, the value is whether it was verified
+func (r *CharacterFlag) GetForCharacters(db *pgxpool.Pool, characterIds []int64) (map[int64]map[int64]bool, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []CharacterFlag
+	q := `SELECT * FROM character_flags WHERE character_id = ANY($1);`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, characterIds); err != nil {
+		return nil, err
+	}
+
+	flags := make(map[int64]map[int64]bool)
+	for _, v := range rows {
+		if _, ok := flags[v.CharacterId]; !ok {
+			flags[v.CharacterId] = make(map[int64]bool)
+		}
+		flags[v.CharacterId][v.FlagId] = v.Verified
+	}
+
+	return flags, nil
+}
+
+// EventFlag is a flag characters need to attend an event
+type EventFlag struct {
+	EventId int64
+	FlagId  int64
+}
+
+// GetForEvent returns the flags an event requires
+func (r *EventFlag) GetForEvent(db *pgxpool.Pool, eventId int64) ([]Flag, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var flags []Flag
+	q := `SELECT f.* FROM flags f JOIN event_flags ef ON ef.flag_id = f.id WHERE ef.event_id = $1 order by f.key;`
+	if err = pgxscan.Select(context.Background(), db, &flags, q, eventId); err != nil {
+		return nil, err
+	}
+
+	return flags, nil
+}
+
+// ReplaceForEvent swaps all flag requirements of an event for the ones given
+func (r *EventFlag) ReplaceForEvent(db *pgxpool.Pool, eventId int64, flagIds []int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = replaceFlags(ctx, tx, eventId, flagIds); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func replaceFlags(ctx context.Context, tx pgx.Tx, eventId int64, flagIds []int64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM event_flags WHERE event_id = $1;`, eventId); err != nil {
+		return err
+	}
+
+	for _, id := range flagIds {
+		if _, err := tx.Exec(ctx, `INSERT INTO event_flags (event_id, flag_id) VALUES ($1, $2);`, eventId, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MissingFlags returns what each character lacks of the flags an event requires keyed by character,
+// characters with every flag are left out
+func MissingFlags(db *pgxpool.Pool, eventId int64, toons []Character) (map[int64][]string, error) {
+	ef := EventFlag{}
+	flags, err := ef.GetForEvent(db, eventId)
+	if err != nil {
+		return nil, err
+	}
+
+	missing := make(map[int64][]string)
+	if len(flags) == 0 || len(toons) == 0 {
+		return missing, nil
+	}
+
+	var (
+		need []int64
+		keys = make(map[int64]string)
+		ids  []int64
+	)
+
+	for _, f := range flags {
+		need = append(need, f.Id)
+		keys[f.Id] = f.Key
+	}
+
+	for _, t := range toons {
+		ids = append(ids, t.Id)
+	}
+
+	cf := CharacterFlag{}
+	have, err := cf.GetForCharacters(db, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, t := range toons {
+		if short := FlagShortfall(have[t.Id], need, keys); len(short) > 0 {
+			missing[t.Id] = short
+		}
+	}
+
+	return missing, nil
+}
+
+// FlagShortfall lists the required flags a character lacks, have maps flag ids to whether an officer verified them,
+// flags that were reported but not verified are listed as unverified
+func FlagShortfall(have map[int64]bool, need []int64, keys map[int64]string) []string {
+	var short []string
+	for _, id := range need {
+		verified, ok := have[id]
+		switch {
+		case !ok:
+			short = append(short, keys[id])
+		case !verified:
+			short = append(short, keys[id]+" (unverified)")
+		}
+	}
+	return short
+}
@@ -0,0 +1,18 @@
+package model_test
+
+import (
+	"eqRaidBot/db/model"
+	"reflect"
+	"testing"
+)
+
+func TestFlagShortfall(t *testing.T) {
+	keys := map[int64]string{1: "vp", 2: "sleeper", 3: "hate"}
+	have := map[int64]bool{1: true, 2: false}
+
+	short := model.FlagShortfall(have, []int64{1, 2, 3}, keys)
+	expected := []string{"sleeper (unverified)", "hate"}
+	if !reflect.DeepEqual(short, expected) {
+		t.Errorf("expected %v, got %v", expected, short)
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// DefaultExpansion is used until officers choose the expansion their server is on
+const DefaultExpansion = "velious"
+
+// GuildSettings holds the rules officers can change for the guild, there is a single row
+type GuildSettings struct {
+	Id        int64
+	MaxBoxes  int64
+	Expansion string
+	UpdatedAt time.Time
+}
+
+func (r *GuildSettings) Get(db *pgxpool.Pool) (GuildSettings, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return GuildSettings{}, err
+	}
+
+	defer conn.Release()
+
+	var settings []GuildSettings
+	if err = pgxscan.Select(context.Background(), db, &settings, `SELECT * FROM guild_settings WHERE id = 1;`); err != nil {
+		return GuildSettings{}, err
+	}
+
+	if len(settings) == 0 {
+		return GuildSettings{Id: 1, MaxBoxes: 1, Expansion: DefaultExpansion}, nil
+	}
+
+	return settings[0], nil
+}
+
+func (r *GuildSettings) Update(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `INSERT INTO guild_settings (id, max_boxes, expansion, updated_at) 
+VALUES (1, $1, $2, NOW()) 
+ON CONFLICT (id) DO UPDATE SET max_boxes = $1, expansion = $2, updated_at = NOW();`, r.MaxBoxes, r.Expansion)
+
+	return err
+}
@@ -0,0 +1,78 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// Notification is a direct message queued for delivery to a discord user
+type Notification struct {
+	Id        int64
+	UserId    string
+	Message   string
+	Sent      bool
+	CreatedAt time.Time
+}
+
+func (r *Notification) Save(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	var row idRow
+
+	err = conn.QueryRow(context.Background(), `INSERT INTO notifications
+	(user_id, message)
+	VALUES ($1, $2) RETURNING id;`,
+		r.UserId,
+		r.Message,
+	).Scan(&row.Id)
+	if err != nil {
+		return err
+	}
+
+	r.Id = row.Id
+
+	return nil
+}
+
+func (r *Notification) MarkSent(db *pgxpool.Pool) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `UPDATE notifications SET sent=true WHERE id=$1;`, r.Id)
+	if err != nil {
+		return err
+	}
+
+	r.Sent = true
+
+	return nil
+}
+
+func (r *Notification) GetPending(db *pgxpool.Pool) ([]Notification, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var notifications []Notification
+	q := `SELECT * FROM notifications WHERE sent = false order by id;`
+	if err = pgxscan.Select(context.Background(), db, &notifications, q); err != nil {
+		return nil, err
+	}
+
+	return notifications, nil
+}
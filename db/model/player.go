@@ -0,0 +1,136 @@
+package model
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// linkedOwners selects every discord account linked to the user in $1, including the user themselves
+const linkedOwners = `(SELECT $1::text UNION SELECT pa.user_id FROM player_accounts pa 
+JOIN player_accounts me ON me.player_id = pa.player_id WHERE me.user_id = $1)`
+
+// PlayerAccount links a discord account to the player using it
+type PlayerAccount struct {
+	UserId    string
+	PlayerId  int64
+	CreatedAt time.Time
+}
+
+// LinkAccounts makes both discord accounts belong to the same player, merging their players if both already had one
+func (r *PlayerAccount) LinkAccounts(db *pgxpool.Pool, userId string, otherUserId string) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	playerId, err := playerFor(ctx, tx, userId)
+	if err != nil {
+		return err
+	}
+
+	otherId, err := playerFor(ctx, tx, otherUserId)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case playerId == 0 && otherId == 0:
+		if err = tx.QueryRow(ctx, `INSERT INTO players DEFAULT VALUES RETURNING id;`).Scan(&playerId); err != nil {
+			return err
+		}
+	case playerId == 0:
+		playerId = otherId
+	case otherId != 0 && otherId != playerId:
+		if _, err = tx.Exec(ctx, `UPDATE player_accounts SET player_id = $1 WHERE player_id = $2;`, playerId, otherId); err != nil {
+			return err
+		}
+		if _, err = tx.Exec(ctx, `DELETE FROM players WHERE id = $1;`, otherId); err != nil {
+			return err
+		}
+	}
+
+	for _, id := range []string{userId, otherUserId} {
+		_, err = tx.Exec(ctx, `INSERT INTO player_accounts (user_id, player_id) VALUES ($1, $2) 
+ON CONFLICT (user_id) DO UPDATE SET player_id = $2;`, id, playerId)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+func playerFor(ctx context.Context, tx pgx.Tx, userId string) (int64, error) {
+	var ids []int64
+	if err := pgxscan.Select(ctx, tx, &ids, `SELECT player_id FROM player_accounts WHERE user_id = $1;`, userId); err != nil {
+		return 0, err
+	}
+
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	return ids[0], nil
+}
+
+// Unlink removes a discord account from its player, the account keeps the characters it registered
+func (r *PlayerAccount) Unlink(db *pgxpool.Pool, userId string) error {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return err
+	}
+
+	defer conn.Release()
+
+	_, err = conn.Exec(context.Background(), `DELETE FROM player_accounts WHERE user_id = $1;`, userId)
+
+	return err
+}
+
+// GetLinked returns every discord account linked to the user, including the user
+func (r *PlayerAccount) GetLinked(db *pgxpool.Pool, userId string) ([]string, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var ids []string
+	q := fmt.Sprintf(`SELECT * FROM %s AS linked(user_id) order by user_id;`, linkedOwners)
+	if err = pgxscan.Select(context.Background(), db, &ids, q, userId); err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// GetOwnerKeys maps every linked discord account to a key shared by all accounts of the same player
+func (r *PlayerAccount) GetOwnerKeys(db *pgxpool.Pool) (map[string]string, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var accounts []PlayerAccount
+	if err = pgxscan.Select(context.Background(), db, &accounts, `SELECT * FROM player_accounts;`); err != nil {
+		return nil, err
+	}
+
+	keys := make(map[string]string)
+	for _, a := range accounts {
+		keys[a.UserId] = fmt.Sprintf("player-%d", a.PlayerId)
+	}
+
+	return keys, nil
+}
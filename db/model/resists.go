@@ -0,0 +1,182 @@
+package model
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4"
+	"github.com/jackc/pgx/v4/pgxpool"
+)
+
+// CharacterResist is the last resist value a member reported for one of their characters
+type CharacterResist struct {
+	CharacterId int64
+	Resist      int64
+	Value       int64
+	UpdatedAt   time.Time
+}
+
+// SetForCharacter records the given resists, resists left out keep their previous value
+func (r *CharacterResist) SetForCharacter(db *pgxpool.Pool, characterId int64, resists map[int64]int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	for resist, value := range resists {
+		_, err = tx.Exec(ctx, `INSERT INTO character_resists (character_id, resist, value) VALUES ($1, $2, $3) 
+ON CONFLICT (character_id, resist) DO UPDATE SET value = EXCLUDED.value, updated_at = CURRENT_TIMESTAMP;`,
+			characterId, resist, value)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
+
+// GetForCharacters returns the recorded resists of each character keyed by character and then resist
+func (r *CharacterResist) GetForCharacters(db *pgxpool.Pool, characterIds []int64) (map[int64]map[int64]int64, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []CharacterResist
+	q := `SELECT * FROM character_resists WHERE character_id = ANY($1);`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, characterIds); err != nil {
+		return nil, err
+	}
+
+	resists := make(map[int64]map[int64]int64)
+	for _, v := range rows {
+		if _, ok := resists[v.CharacterId]; !ok {
+			resists[v.CharacterId] = make(map[int64]int64)
+		}
+		resists[v.CharacterId][v.Resist] = v.Value
+	}
+
+	return resists, nil
+}
+
+// EventResist is the minimum resist characters need to attend an event
+type EventResist struct {
+	EventId int64
+	Resist  int64
+	Minimum int64
+}
+
+// GetForEvent returns the resist requirements of an event keyed by resist
+func (r *EventResist) GetForEvent(db *pgxpool.Pool, eventId int64) (map[int64]int64, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []EventResist
+	q := `SELECT * FROM event_resists WHERE event_id = $1;`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, eventId); err != nil {
+		return nil, err
+	}
+
+	resists := make(map[int64]int64)
+	for _, v := range rows {
+		resists[v.Resist] = v.Minimum
+	}
+
+	return resists, nil
+}
+
+// ReplaceForEvent swaps all resist requirements of an event for the ones given
+func (r *EventResist) ReplaceForEvent(db *pgxpool.Pool, eventId int64, resists map[int64]int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if err = replaceResists(ctx, tx, eventId, resists); err != nil {
+		return err
+	}
+
+	return tx.Commit(ctx)
+}
+
+func replaceResists(ctx context.Context, tx pgx.Tx, eventId int64, resists map[int64]int64) error {
+	if _, err := tx.Exec(ctx, `DELETE FROM event_resists WHERE event_id = $1;`, eventId); err != nil {
+		return err
+	}
+
+	for resist, min := range resists {
+		_, err := tx.Exec(ctx, `INSERT INTO event_resists (event_id, resist, minimum) VALUES ($1, $2, $3);`, eventId, resist, min)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// TemplateResist is a resist requirement copied to events created from a template
+type TemplateResist struct {
+	TemplateId int64
+	Resist     int64
+	Minimum    int64
+}
+
+// GetForTemplate returns the resist requirements of a template keyed by resist
+func (r *TemplateResist) GetForTemplate(db *pgxpool.Pool, templateId int64) (map[int64]int64, error) {
+	conn, err := db.Acquire(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	defer conn.Release()
+
+	var rows []TemplateResist
+	q := `SELECT * FROM template_resists WHERE template_id = $1;`
+	if err = pgxscan.Select(context.Background(), db, &rows, q, templateId); err != nil {
+		return nil, err
+	}
+
+	resists := make(map[int64]int64)
+	for _, v := range rows {
+		resists[v.Resist] = v.Minimum
+	}
+
+	return resists, nil
+}
+
+// ReplaceForTemplate swaps all resist requirements of a template for the ones given
+func (r *TemplateResist) ReplaceForTemplate(db *pgxpool.Pool, templateId int64, resists map[int64]int64) error {
+	ctx := context.Background()
+	tx, err := db.Begin(ctx)
+	if err != nil {
+		return err
+	}
+
+	defer tx.Rollback(ctx)
+
+	if _, err = tx.Exec(ctx, `DELETE FROM template_resists WHERE template_id = $1;`, templateId); err != nil {
+		return err
+	}
+
+	for resist, min := range resists {
+		_, err = tx.Exec(ctx, `INSERT INTO template_resists (template_id, resist, minimum) VALUES ($1, $2, $3);`, templateId, resist, min)
+		if err != nil {
+			return err
+		}
+	}
+
+	return tx.Commit(ctx)
+}
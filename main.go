@@ -2,11 +2,14 @@ package main
 
 import (
 	"eqRaidBot/bot"
+	"eqRaidBot/bot/eq"
 	"eqRaidBot/db"
+	"eqRaidBot/db/model"
 	"fmt"
 	"log"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -18,7 +21,13 @@ import (
 type config struct {
 	DiscordToken string `env:"TOKEN"`
 	DbURI        string `env:"DB_URI"`
-	Extras       env.EnvSet
+	// channel that event announcements are posted to, announcements are disabled when empty
+	AnnounceChannel string `env:"ANNOUNCE_CHANNEL"`
+	// either skip or allow, decides whether characters are auto signed up to overlapping events
+	OverlapPolicy string `env:"OVERLAP_POLICY"`
+	// overrides the AA cap of every expansion from luclin on, each expansion keeps its own cap when empty
+	MaxAA  string `env:"MAX_AA"`
+	Extras env.EnvSet
 }
 
 func main() {
@@ -30,14 +39,32 @@ func main() {
 		log.Fatal(fmt.Sprintf("Error creating discord session: %s", err.Error()))
 	}
 
+	if conf.MaxAA != "" {
+		maxAA, err := strconv.ParseInt(conf.MaxAA, 10, 64)
+		if err != nil {
+			log.Fatal(fmt.Sprintf("MAX_AA must be a number: %s", err.Error()))
+		}
+		eq.SetMaxAA(maxAA)
+	}
+
 	conn, err := db.NewPgPool(conf.DbURI)
 	if err != nil {
 		log.Fatal(fmt.Sprintf("problem establishing connection to db: %s", err.Error()))
 	}
 
+	gs := model.GuildSettings{}
+	settings, err := gs.Get(conn)
+	if err != nil {
+		log.Fatal(fmt.Sprintf("problem loading the guild settings: %s", err.Error()))
+	}
+
+	if err = eq.SetRuleset(settings.Expansion); err != nil {
+		log.Fatal(err.Error())
+	}
+
 	cmds := bot.NewCommandController(conn)
 
-	autoAttender := bot.NewAutoAttender(conn)
+	autoAttender := bot.NewAutoAttender(conn, bot.OverlapPolicy(conf.OverlapPolicy))
 	eventWatcher := bot.NewEventWatcher(conn)
 
 	ac := make(chan struct{})
@@ -63,6 +90,17 @@ func main() {
 		log.Fatal(fmt.Sprintf("Error opening connection %s", err.Error()))
 	}
 
+	nc := make(chan struct{})
+	notifier := bot.NewNotifier(conn, dg)
+	go notifier.Run(nc, 30*time.Second)
+
+	var anc chan struct{}
+	if conf.AnnounceChannel != "" {
+		anc = make(chan struct{})
+		announcer := bot.NewAnnouncer(conn, dg, conf.AnnounceChannel)
+		go announcer.Run(anc, time.Minute)
+	}
+
 	log.Printf("EqRaidBot is online. Press CTRL+C to terminate.\n")
 
 	sc := make(chan os.Signal, 1)
@@ -70,6 +108,10 @@ func main() {
 	<-sc
 	ac <- struct{}{}
 	ec <- struct{}{}
+	nc <- struct{}{}
+	if anc != nil {
+		anc <- struct{}{}
+	}
 	log.Println("Shutting down")
 }
 
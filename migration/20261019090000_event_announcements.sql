@@ -0,0 +1,24 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events
+    ADD COLUMN status smallint NOT NULL DEFAULT 1;
+
+CREATE TABLE IF NOT EXISTS event_announcements (
+    event_id bigint PRIMARY KEY,
+    channel_id varchar(255) NOT NULL,
+    message_id varchar(255) NOT NULL,
+    content text NOT NULL,
+    closed boolean NOT NULL DEFAULT false,
+    updated_at timestamp NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_announcements;
+ALTER TABLE events
+    DROP COLUMN status;
+-- +goose StatementEnd
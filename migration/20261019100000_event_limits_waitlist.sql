@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE events
+    ADD COLUMN max_attendees integer NOT NULL DEFAULT 0,
+    ADD COLUMN min_level smallint NOT NULL DEFAULT 0,
+    ADD COLUMN allowed_types integer[] NOT NULL DEFAULT '{1,2}';
+
+CREATE TABLE IF NOT EXISTS event_class_caps (
+    event_id bigint NOT NULL,
+    class smallint NOT NULL,
+    cap integer NOT NULL,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id)
+);
+
+CREATE UNIQUE INDEX event_class_idx ON event_class_caps(event_id, class);
+
+-- id gives the waitlist a stable order, batch inserts share a created_at
+ALTER TABLE attendance
+    ADD COLUMN id BIGSERIAL,
+    ADD COLUMN waitlisted boolean NOT NULL DEFAULT false;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGSERIAL PRIMARY KEY,
+    user_id varchar(255) NOT NULL,
+    message text NOT NULL,
+    sent boolean NOT NULL DEFAULT false,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE notifications;
+ALTER TABLE attendance
+    DROP COLUMN id,
+    DROP COLUMN waitlisted;
+DROP TABLE event_class_caps;
+ALTER TABLE events
+    DROP COLUMN max_attendees,
+    DROP COLUMN min_level,
+    DROP COLUMN allowed_types;
+-- +goose StatementEnd
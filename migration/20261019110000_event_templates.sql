@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS event_templates (
+    id BIGSERIAL PRIMARY KEY,
+    name varchar(100) NOT NULL,
+    description text NOT NULL,
+    min_level smallint NOT NULL DEFAULT 0,
+    split_count smallint NOT NULL DEFAULT 0,
+    is_repeatable boolean NOT NULL,
+    created_by varchar(255) NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX event_template_name_idx ON event_templates(lower(name));
+
+ALTER TABLE events
+    ADD COLUMN split_count smallint NOT NULL DEFAULT 0;
+
+-- events created from templates and weekly renewals share their title
+DROP INDEX event_title_idx;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+CREATE UNIQUE INDEX event_title_idx ON events(title);
+ALTER TABLE events
+    DROP COLUMN split_count;
+DROP TABLE event_templates;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- signed up is tracked by the row itself, attended records who actually showed up
+ALTER TABLE attendance
+    ADD COLUMN attended boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE attendance
+    DROP COLUMN attended;
+-- +goose StatementEnd
@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- duration is stored in minutes
+ALTER TABLE events
+    ADD COLUMN duration integer NOT NULL DEFAULT 240;
+
+ALTER TABLE event_templates
+    ADD COLUMN duration integer NOT NULL DEFAULT 240;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE event_templates
+    DROP COLUMN duration;
+ALTER TABLE events
+    DROP COLUMN duration;
+-- +goose StatementEnd
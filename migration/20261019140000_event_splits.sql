@@ -0,0 +1,21 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS event_splits (
+    event_id bigint NOT NULL,
+    raid_number smallint NOT NULL,
+    group_number smallint NOT NULL,
+    character_id bigint NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id),
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+
+CREATE INDEX event_split_idx ON event_splits(event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_splits;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- retired characters keep their attendance history but take no further part in events
+ALTER TABLE characters
+    ADD COLUMN retired boolean NOT NULL DEFAULT false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE characters
+    DROP COLUMN retired;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+-- characters imported from a guild dump have no owner until they are claimed
+ALTER TABLE characters
+    ADD COLUMN rank varchar(255) NOT NULL default '',
+    ADD COLUMN notes varchar(255) NOT NULL default '',
+    ADD COLUMN last_on timestamp NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE characters
+    DROP COLUMN rank,
+    DROP COLUMN notes,
+    DROP COLUMN last_on;
+-- +goose StatementEnd
@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- kills and loot are read from uploaded log files, several officers may upload logs covering the same raid
+CREATE TABLE IF NOT EXISTS event_kills (
+    id BIGSERIAL PRIMARY KEY,
+    event_id bigint NOT NULL,
+    mob varchar(255) NOT NULL,
+    killed_by varchar(255) NOT NULL,
+    killed_at timestamp NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id)
+);
+
+-- logs from different officers disagree by a few seconds so duplicates are matched by the bot rather than an index
+CREATE INDEX event_kills_event_idx ON event_kills(event_id);
+
+CREATE TABLE IF NOT EXISTS event_loot (
+    id BIGSERIAL PRIMARY KEY,
+    event_id bigint NOT NULL,
+    character_name varchar(255) NOT NULL,
+    character_id bigint,
+    item varchar(255) NOT NULL,
+    looted_at timestamp NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id),
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+
+CREATE INDEX event_loot_event_idx ON event_loot(event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_loot;
+DROP TABLE event_kills;
+-- +goose StatementEnd
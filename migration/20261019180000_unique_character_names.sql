@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+-- names are unique on a server among characters still in use, the first registration keeps the name
+-- and newer duplicates get their id as a suffix so an officer can sort them out
+UPDATE characters c
+SET name = c.name || '_' || c.id
+WHERE c.retired = false
+  AND EXISTS(SELECT 1 FROM characters d WHERE lower(d.name) = lower(c.name) AND d.retired = false AND d.id < c.id);
+
+-- retired characters keep their name but do not stop someone registering it again
+CREATE UNIQUE INDEX characters_name_uniq_idx ON characters (lower(name)) WHERE retired = false;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX characters_name_uniq_idx;
+-- +goose StatementEnd
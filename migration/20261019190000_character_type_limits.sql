@@ -0,0 +1,89 @@
+-- +goose Up
+-- +goose StatementBegin
+-- settings for the guild the bot serves, there is only ever one row
+CREATE TABLE IF NOT EXISTS guild_settings (
+    id smallint PRIMARY KEY DEFAULT 1 CHECK (id = 1),
+    max_boxes smallint NOT NULL DEFAULT 1 CHECK (max_boxes >= 0),
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+INSERT INTO guild_settings (id) VALUES (1);
+
+-- earlier registrations were only checked by the bot, keep the oldest main and box and make the rest alts
+UPDATE characters c SET character_type = 3
+WHERE c.character_type IN (1, 2) AND c.retired = false AND c.created_by <> ''
+AND EXISTS (SELECT 1 FROM characters d
+    WHERE d.created_by = c.created_by AND d.character_type = c.character_type AND d.retired = false AND d.id < c.id);
+
+CREATE UNIQUE INDEX characters_one_main_idx ON characters(created_by)
+    WHERE character_type = 2 AND retired = false AND created_by <> '';
+
+-- each box an owner has takes one of the guilds box slots, the index stops two boxes sharing a slot
+ALTER TABLE characters
+    ADD COLUMN box_slot smallint NULL;
+
+UPDATE characters SET box_slot = 1 WHERE character_type = 1 AND retired = false AND created_by <> '';
+
+CREATE UNIQUE INDEX characters_box_slot_idx ON characters(created_by, box_slot)
+    WHERE character_type = 1 AND retired = false AND created_by <> '';
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION assign_box_slot() RETURNS trigger AS
+$$
+DECLARE
+    slot smallint;
+BEGIN
+    IF NEW.character_type <> 1 OR NEW.retired OR NEW.created_by = '' THEN
+        NEW.box_slot := NULL;
+        RETURN NEW;
+    END IF;
+
+    IF TG_OP = 'UPDATE' AND OLD.box_slot IS NOT NULL AND OLD.character_type = 1 AND NOT OLD.retired
+        AND OLD.created_by = NEW.created_by THEN
+        NEW.box_slot := OLD.box_slot;
+        RETURN NEW;
+    END IF;
+
+    SELECT s
+    INTO slot
+    FROM generate_series(1, (SELECT max_boxes FROM guild_settings WHERE id = 1)) s
+    WHERE s NOT IN (SELECT box_slot
+                    FROM characters
+                    WHERE created_by = NEW.created_by
+                      AND character_type = 1
+                      AND retired = false
+                      AND box_slot IS NOT NULL
+                      AND id <> NEW.id)
+    ORDER BY s
+    LIMIT 1;
+
+    IF slot IS NULL THEN
+        RAISE EXCEPTION 'owner % has no free box slots', NEW.created_by
+            USING ERRCODE = 'unique_violation', CONSTRAINT = 'characters_box_slot_idx';
+    END IF;
+
+    NEW.box_slot := slot;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER characters_box_slot_trg
+    BEFORE INSERT OR UPDATE OF character_type, retired, created_by
+    ON characters
+    FOR EACH ROW
+EXECUTE FUNCTION assign_box_slot();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER characters_box_slot_trg ON characters;
+DROP FUNCTION assign_box_slot();
+DROP INDEX characters_box_slot_idx;
+DROP INDEX characters_one_main_idx;
+ALTER TABLE characters
+    DROP COLUMN box_slot;
+DROP TABLE guild_settings;
+-- +goose StatementEnd
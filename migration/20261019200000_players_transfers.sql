@@ -0,0 +1,39 @@
+-- +goose Up
+-- +goose StatementBegin
+-- a player is one person, they may use several discord accounts which all own their characters
+CREATE TABLE IF NOT EXISTS players (
+    id BIGSERIAL PRIMARY KEY,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS player_accounts (
+    user_id varchar(255) PRIMARY KEY,
+    player_id bigint NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(player_id)
+        REFERENCES players(id)
+        ON DELETE CASCADE
+);
+
+CREATE INDEX player_accounts_player_idx ON player_accounts(player_id);
+
+CREATE TABLE IF NOT EXISTS character_transfers (
+    id BIGSERIAL PRIMARY KEY,
+    character_id bigint NOT NULL,
+    from_user varchar(255) NOT NULL,
+    to_user varchar(255) NOT NULL,
+    status smallint NOT NULL default 1,
+    decided_by varchar(255) NOT NULL default '',
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE character_transfers;
+DROP TABLE player_accounts;
+DROP TABLE players;
+-- +goose StatementEnd
@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the expansion decides which classes can be registered and the level cap
+ALTER TABLE guild_settings
+    ADD COLUMN expansion varchar(255) NOT NULL default 'velious';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE guild_settings
+    DROP COLUMN expansion;
+-- +goose StatementEnd
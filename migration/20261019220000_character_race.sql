@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- 0 is an unknown race for characters registered before races were tracked
+ALTER TABLE characters
+    ADD COLUMN race smallint NOT NULL DEFAULT 0;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE characters
+    DROP COLUMN race;
+-- +goose StatementEnd
@@ -0,0 +1,41 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS character_resists (
+    character_id bigint NOT NULL,
+    resist smallint NOT NULL,
+    value integer NOT NULL,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+
+CREATE UNIQUE INDEX character_resist_idx ON character_resists(character_id, resist);
+
+CREATE TABLE IF NOT EXISTS event_resists (
+    event_id bigint NOT NULL,
+    resist smallint NOT NULL,
+    minimum integer NOT NULL,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id)
+);
+
+CREATE UNIQUE INDEX event_resist_idx ON event_resists(event_id, resist);
+
+CREATE TABLE IF NOT EXISTS template_resists (
+    template_id bigint NOT NULL,
+    resist smallint NOT NULL,
+    minimum integer NOT NULL,
+    FOREIGN KEY(template_id)
+        REFERENCES event_templates(id)
+        ON DELETE CASCADE
+);
+
+CREATE UNIQUE INDEX template_resist_idx ON template_resists(template_id, resist);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE template_resists;
+DROP TABLE event_resists;
+DROP TABLE character_resists;
+-- +goose StatementEnd
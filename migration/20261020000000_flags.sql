@@ -0,0 +1,46 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS flags (
+    id BIGSERIAL PRIMARY KEY,
+    key varchar(20) NOT NULL,
+    description text NOT NULL,
+    created_by varchar(255) NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX flags_key_idx ON flags(key);
+
+-- members report their own flags, they only count once an officer verifies them
+CREATE TABLE IF NOT EXISTS character_flags (
+    character_id bigint NOT NULL,
+    flag_id bigint NOT NULL,
+    verified boolean NOT NULL DEFAULT false,
+    verified_by varchar(255) NOT NULL DEFAULT '',
+    reported_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    verified_at timestamp,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id),
+    FOREIGN KEY(flag_id)
+        REFERENCES flags(id)
+);
+
+CREATE UNIQUE INDEX character_flag_idx ON character_flags(character_id, flag_id);
+
+CREATE TABLE IF NOT EXISTS event_flags (
+    event_id bigint NOT NULL,
+    flag_id bigint NOT NULL,
+    FOREIGN KEY(event_id)
+        REFERENCES events(id),
+    FOREIGN KEY(flag_id)
+        REFERENCES flags(id)
+);
+
+CREATE UNIQUE INDEX event_flag_idx ON event_flags(event_id, flag_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE event_flags;
+DROP TABLE character_flags;
+DROP TABLE flags;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the steps of each class epic are kept by officers, the finished weapon is not a row and always comes last
+CREATE TABLE IF NOT EXISTS epic_steps (
+    id BIGSERIAL PRIMARY KEY,
+    class smallint NOT NULL,
+    position smallint NOT NULL,
+    name varchar(255) NOT NULL,
+    created_by varchar(255) NOT NULL,
+    created_at timestamp NOT NULL default CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX epic_steps_class_position_idx ON epic_steps(class, position);
+
+-- step is the number of catalog steps completed, finished is set once the weapon is done
+CREATE TABLE IF NOT EXISTS character_epics (
+    character_id bigint PRIMARY KEY,
+    step smallint NOT NULL DEFAULT 0,
+    finished boolean NOT NULL DEFAULT false,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE character_epics;
+DROP TABLE epic_steps;
+-- +goose StatementEnd
@@ -0,0 +1,19 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE IF NOT EXISTS character_tradeskills (
+    character_id bigint NOT NULL,
+    skill smallint NOT NULL,
+    value integer NOT NULL,
+    updated_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+
+CREATE UNIQUE INDEX character_tradeskill_idx ON character_tradeskills(character_id, skill);
+CREATE INDEX character_tradeskill_value_idx ON character_tradeskills(skill, value);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE character_tradeskills;
+-- +goose StatementEnd
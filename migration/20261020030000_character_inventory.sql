@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- the latest /outputfile inventory upload of each character, uploads replace all rows of the character
+CREATE TABLE IF NOT EXISTS character_inventory (
+    character_id bigint NOT NULL,
+    location varchar(50) NOT NULL,
+    name varchar(255) NOT NULL,
+    item_id bigint NOT NULL,
+    count integer NOT NULL,
+    slots smallint NOT NULL default 0,
+    imported_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+
+CREATE INDEX character_inventory_character_idx ON character_inventory(character_id);
+CREATE INDEX character_inventory_name_idx ON character_inventory(lower(name));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE character_inventory;
+-- +goose StatementEnd
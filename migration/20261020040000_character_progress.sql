@@ -0,0 +1,48 @@
+-- +goose Up
+-- +goose StatementBegin
+-- every level and AA a character has had, written by a trigger so edits, roster imports and log imports are all recorded
+CREATE TABLE IF NOT EXISTS character_progress (
+    id BIGSERIAL PRIMARY KEY,
+    character_id bigint NOT NULL,
+    level smallint NOT NULL,
+    aa integer NOT NULL,
+    recorded_at timestamp NOT NULL default CURRENT_TIMESTAMP,
+    FOREIGN KEY(character_id)
+        REFERENCES characters(id)
+);
+
+CREATE INDEX character_progress_character_idx ON character_progress(character_id, recorded_at);
+
+-- characters that existed before history was kept start from what they are today
+INSERT INTO character_progress (character_id, level, aa)
+SELECT id, level, aa FROM characters;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE FUNCTION record_character_progress() RETURNS trigger AS
+$$
+BEGIN
+    IF TG_OP = 'UPDATE' AND NEW.level = OLD.level AND NEW.aa = OLD.aa THEN
+        RETURN NEW;
+    END IF;
+
+    INSERT INTO character_progress (character_id, level, aa) VALUES (NEW.id, NEW.level, NEW.aa);
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+CREATE TRIGGER characters_progress_trg
+    AFTER INSERT OR UPDATE OF level, aa
+    ON characters
+    FOR EACH ROW
+EXECUTE FUNCTION record_character_progress();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TRIGGER characters_progress_trg ON characters;
+DROP FUNCTION record_character_progress();
+DROP TABLE character_progress;
+-- +goose StatementEnd
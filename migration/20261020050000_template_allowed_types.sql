@@ -0,0 +1,12 @@
+-- +goose Up
+-- +goose StatementBegin
+-- templates carry the attendance policy so an alt raid template always makes alt raids
+ALTER TABLE event_templates
+    ADD COLUMN allowed_types integer[] NOT NULL DEFAULT '{1,2}';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE event_templates
+    DROP COLUMN allowed_types;
+-- +goose StatementEnd